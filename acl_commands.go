@@ -0,0 +1,453 @@
+package go_redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type AclCmdable interface {
+	AclSetUser(ctx context.Context, username string, rules ...string) *StatusCmd
+	AclGetUser(ctx context.Context, username string) *AclUserCmd
+	AclDelUser(ctx context.Context, usernames ...string) *IntCmd
+	AclList(ctx context.Context) *StringSliceCmd
+	AclUsers(ctx context.Context) *StringSliceCmd
+	AclWhoAmI(ctx context.Context) *StringCmd
+	AclCat(ctx context.Context, category string) *StringSliceCmd
+	AclLog(ctx context.Context, count int64) *AclLogCmd
+	AclLogReset(ctx context.Context) *StatusCmd
+	AclGenPass(ctx context.Context, bits int) *StringCmd
+	AclDryRun(ctx context.Context, username string, command ...interface{}) *StringCmd
+}
+
+// AclRules builds the rule list of ACL SETUSER:
+//
+//	rules := NewAclRules().Reset().On().Password("s3cret").
+//		Keys("billing:*").AllowCategory("read").DenyCommand("keys")
+//	redis.AclSetUser(ctx, "billing", rules.Rules()...)
+type AclRules struct {
+	rules []string
+}
+
+func NewAclRules() *AclRules {
+	return &AclRules{}
+}
+
+// Rules returns the rules in the order they were added.
+func (r *AclRules) Rules() []string {
+	return r.rules
+}
+
+// Rule appends a raw ACL rule.
+func (r *AclRules) Rule(rule string) *AclRules {
+	r.rules = append(r.rules, rule)
+	return r
+}
+
+// Reset removes every permission, password and key pattern of the user.
+func (r *AclRules) Reset() *AclRules {
+	return r.Rule("reset")
+}
+
+func (r *AclRules) On() *AclRules {
+	return r.Rule("on")
+}
+
+func (r *AclRules) Off() *AclRules {
+	return r.Rule("off")
+}
+
+func (r *AclRules) NoPass() *AclRules {
+	return r.Rule("nopass")
+}
+
+func (r *AclRules) ResetPass() *AclRules {
+	return r.Rule("resetpass")
+}
+
+func (r *AclRules) Password(password string) *AclRules {
+	return r.Rule(">" + password)
+}
+
+func (r *AclRules) RemovePassword(password string) *AclRules {
+	return r.Rule("<" + password)
+}
+
+// PasswordHash adds a SHA-256 hex encoded password.
+func (r *AclRules) PasswordHash(hash string) *AclRules {
+	return r.Rule("#" + hash)
+}
+
+func (r *AclRules) AllKeys() *AclRules {
+	return r.Rule("allkeys")
+}
+
+func (r *AclRules) ResetKeys() *AclRules {
+	return r.Rule("resetkeys")
+}
+
+// Keys allows read and write access to keys matching the patterns.
+func (r *AclRules) Keys(patterns ...string) *AclRules {
+	for _, pattern := range patterns {
+		r.Rule("~" + pattern)
+	}
+	return r
+}
+
+// ReadKeys allows read-only access to keys matching the patterns.
+func (r *AclRules) ReadKeys(patterns ...string) *AclRules {
+	for _, pattern := range patterns {
+		r.Rule("%R~" + pattern)
+	}
+	return r
+}
+
+// WriteKeys allows write-only access to keys matching the patterns.
+func (r *AclRules) WriteKeys(patterns ...string) *AclRules {
+	for _, pattern := range patterns {
+		r.Rule("%W~" + pattern)
+	}
+	return r
+}
+
+func (r *AclRules) AllChannels() *AclRules {
+	return r.Rule("allchannels")
+}
+
+func (r *AclRules) ResetChannels() *AclRules {
+	return r.Rule("resetchannels")
+}
+
+func (r *AclRules) Channels(patterns ...string) *AclRules {
+	for _, pattern := range patterns {
+		r.Rule("&" + pattern)
+	}
+	return r
+}
+
+func (r *AclRules) AllCommands() *AclRules {
+	return r.Rule("allcommands")
+}
+
+func (r *AclRules) NoCommands() *AclRules {
+	return r.Rule("nocommands")
+}
+
+// AllowCommand allows a command or a command|subcommand.
+func (r *AclRules) AllowCommand(commands ...string) *AclRules {
+	for _, command := range commands {
+		r.Rule("+" + strings.ToLower(command))
+	}
+	return r
+}
+
+func (r *AclRules) DenyCommand(commands ...string) *AclRules {
+	for _, command := range commands {
+		r.Rule("-" + strings.ToLower(command))
+	}
+	return r
+}
+
+func (r *AclRules) AllowCategory(categories ...string) *AclRules {
+	for _, category := range categories {
+		r.Rule("+@" + strings.ToLower(category))
+	}
+	return r
+}
+
+func (r *AclRules) DenyCategory(categories ...string) *AclRules {
+	for _, category := range categories {
+		r.Rule("-@" + strings.ToLower(category))
+	}
+	return r
+}
+
+// Selector adds a (...) selector made of the given rules.
+func (r *AclRules) Selector(rules *AclRules) *AclRules {
+	return r.Rule("(" + strings.Join(rules.rules, " ") + ")")
+}
+
+func (r *AclRules) ClearSelectors() *AclRules {
+	return r.Rule("clearselectors")
+}
+
+func (c cmdable) AclSetUser(ctx context.Context, username string, rules ...string) *StatusCmd {
+	args := make([]interface{}, 3, 3+len(rules))
+	args[0] = "ACL"
+	args[1] = "SETUSER"
+	args[2] = username
+	for _, rule := range rules {
+		args = append(args, rule)
+	}
+	cmd := newStatusCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) AclGetUser(ctx context.Context, username string) *AclUserCmd {
+	cmd := &AclUserCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: []interface{}{"ACL", "GETUSER", username},
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) AclDelUser(ctx context.Context, usernames ...string) *IntCmd {
+	args := make([]interface{}, 2, 2+len(usernames))
+	args[0] = "ACL"
+	args[1] = "DELUSER"
+	for _, username := range usernames {
+		args = append(args, username)
+	}
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) AclList(ctx context.Context) *StringSliceCmd {
+	cmd := newStringSliceCmd(ctx, "ACL", "LIST")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) AclUsers(ctx context.Context) *StringSliceCmd {
+	cmd := newStringSliceCmd(ctx, "ACL", "USERS")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) AclWhoAmI(ctx context.Context) *StringCmd {
+	cmd := newStringCmd(ctx, "ACL", "WHOAMI")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// AclCat lists the categories, or the commands of category if not empty.
+func (c cmdable) AclCat(ctx context.Context, category string) *StringSliceCmd {
+	args := []interface{}{"ACL", "CAT"}
+	if category != "" {
+		args = append(args, category)
+	}
+	cmd := newStringSliceCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// AclLog returns the most recent security events, at most count if > 0.
+func (c cmdable) AclLog(ctx context.Context, count int64) *AclLogCmd {
+	args := []interface{}{"ACL", "LOG"}
+	if count > 0 {
+		args = append(args, count)
+	}
+	cmd := &AclLogCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) AclLogReset(ctx context.Context) *StatusCmd {
+	cmd := newStatusCmd(ctx, "ACL", "LOG", "RESET")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// AclGenPass generates a random password of bits (default 256) bits.
+func (c cmdable) AclGenPass(ctx context.Context, bits int) *StringCmd {
+	args := []interface{}{"ACL", "GENPASS"}
+	if bits > 0 {
+		args = append(args, bits)
+	}
+	cmd := newStringCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// AclDryRun replies OK if username may run command, otherwise the reason
+// it would be denied.
+func (c cmdable) AclDryRun(ctx context.Context, username string, command ...interface{}) *StringCmd {
+	args := make([]interface{}, 3, 3+len(command))
+	args[0] = "ACL"
+	args[1] = "DRYRUN"
+	args[2] = username
+	args = append(args, command...)
+	cmd := newStringCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+type AclUser struct {
+	Flags     []string
+	Passwords []string
+	Commands  string
+	Keys      string
+	Channels  string
+	Selectors []map[string]string
+}
+
+type AclUserCmd struct {
+	*baseCmd
+	result *AclUser
+}
+
+func (cmd *AclUserCmd) ReadReply(val interface{}) error {
+	pairs, err := toPairs(val)
+	if err != nil {
+		return err
+	}
+	user := &AclUser{}
+	for i := 0; i < len(pairs); i += 2 {
+		k, err := toString(pairs[i])
+		if err != nil {
+			return err
+		}
+		v := pairs[i+1]
+		switch k {
+		case "flags":
+			user.Flags, err = toStrings(v)
+		case "passwords":
+			user.Passwords, err = toStrings(v)
+		case "commands":
+			user.Commands, err = toString(v)
+		case "keys":
+			user.Keys, err = aclPatterns(v)
+		case "channels":
+			user.Channels, err = aclPatterns(v)
+		case "selectors":
+			var selectors []interface{}
+			if selectors, err = toSlice(v); err != nil {
+				break
+			}
+			for _, selector := range selectors {
+				var m map[string]string
+				if m, err = toStringMap(selector); err != nil {
+					break
+				}
+				user.Selectors = append(user.Selectors, m)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	cmd.result = user
+	return nil
+}
+
+// aclPatterns accepts the space separated string of Redis 7 as well as the
+// array of patterns replied by Redis 6.
+func aclPatterns(val interface{}) (string, error) {
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+	patterns, err := toStrings(val)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(patterns, " "), nil
+}
+
+func (cmd *AclUserCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *AclUserCmd) Result() (*AclUser, error) {
+	return cmd.result, cmd.err
+}
+
+type AclLogEntry struct {
+	Count                int64
+	Reason               string
+	Context              string
+	Object               string
+	Username             string
+	Age                  time.Duration
+	ClientInfo           *ClientInfo
+	EntryID              int64
+	TimestampCreated     time.Time
+	TimestampLastUpdated time.Time
+}
+
+type AclLogCmd struct {
+	*baseCmd
+	result []*AclLogEntry
+}
+
+func (cmd *AclLogCmd) ReadReply(val interface{}) error {
+	entries, err := toSlice(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = make([]*AclLogEntry, len(entries))
+	for i, e := range entries {
+		if cmd.result[i], err = parseAclLogEntry(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseAclLogEntry(val interface{}) (*AclLogEntry, error) {
+	pairs, err := toPairs(val)
+	if err != nil {
+		return nil, err
+	}
+	entry := &AclLogEntry{}
+	for i := 0; i < len(pairs); i += 2 {
+		k, err := toString(pairs[i])
+		if err != nil {
+			return nil, err
+		}
+		v := pairs[i+1]
+		switch k {
+		case "count":
+			entry.Count, err = toInt64(v)
+		case "reason":
+			entry.Reason, err = toString(v)
+		case "context":
+			entry.Context, err = toString(v)
+		case "object":
+			entry.Object, err = toString(v)
+		case "username":
+			entry.Username, err = toString(v)
+		case "age-seconds":
+			var age float64
+			age, err = toFloat64(v)
+			entry.Age = time.Duration(age * float64(time.Second))
+		case "client-info":
+			var s string
+			if s, err = toString(v); err == nil {
+				entry.ClientInfo, err = parseClientInfo(s)
+			}
+		case "entry-id":
+			entry.EntryID, err = toInt64(v)
+		case "timestamp-created":
+			var ms int64
+			ms, err = toInt64(v)
+			entry.TimestampCreated = time.UnixMilli(ms)
+		case "timestamp-last-updated":
+			var ms int64
+			ms, err = toInt64(v)
+			entry.TimestampLastUpdated = time.UnixMilli(ms)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+func (cmd *AclLogCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *AclLogCmd) Result() ([]*AclLogEntry, error) {
+	return cmd.result, cmd.err
+}
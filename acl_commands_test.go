@@ -0,0 +1,132 @@
+package go_redis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAclRules(t *testing.T) {
+	rules := NewAclRules().Reset().On().Password("s3cret").PasswordHash("abc").
+		Keys("billing:*", "invoice:*").ReadKeys("ro:*").WriteKeys("wo:*").Channels("events").
+		AllowCategory("READ").DenyCategory("dangerous").AllowCommand("CLIENT|SETNAME").DenyCommand("KEYS").
+		Selector(NewAclRules().Keys("other:*").AllowCommand("get")).
+		Rules()
+	want := []string{"reset", "on", ">s3cret", "#abc",
+		"~billing:*", "~invoice:*", "%R~ro:*", "%W~wo:*", "&events",
+		"+@read", "-@dangerous", "+client|setname", "-keys",
+		"(~other:* +get)"}
+	if !reflect.DeepEqual(rules, want) {
+		t.Fatalf("got %v, want %v", rules, want)
+	}
+}
+
+func TestAclUserCmd(t *testing.T) {
+	want := &AclUser{
+		Flags:     []string{"on"},
+		Passwords: []string{"e5e9fa1b"},
+		Commands:  "+@read",
+		Keys:      "~billing:* %R~ro:*",
+		Channels:  "&events",
+		Selectors: []map[string]string{{"commands": "+get", "keys": "~other:*", "channels": ""}},
+	}
+
+	replies := map[string]interface{}{
+		"resp2": []interface{}{
+			"flags", []interface{}{"on"},
+			"passwords", []interface{}{"e5e9fa1b"},
+			"commands", "+@read",
+			"keys", "~billing:* %R~ro:*",
+			"channels", "&events",
+			"selectors", []interface{}{
+				[]interface{}{"commands", "+get", "keys", "~other:*", "channels", ""},
+			},
+		},
+		"resp3": map[interface{}]interface{}{
+			"flags":     []interface{}{"on"},
+			"passwords": []interface{}{"e5e9fa1b"},
+			"commands":  "+@read",
+			"keys":      "~billing:* %R~ro:*",
+			"channels":  "&events",
+			"selectors": []interface{}{
+				map[interface{}]interface{}{"commands": "+get", "keys": "~other:*", "channels": ""},
+			},
+		},
+		// Redis 6 replies the patterns as arrays
+		"redis6": []interface{}{
+			"flags", []interface{}{"on"},
+			"passwords", []interface{}{"e5e9fa1b"},
+			"commands", "+@read",
+			"keys", []interface{}{"~billing:*", "%R~ro:*"},
+			"channels", []interface{}{"&events"},
+			"selectors", []interface{}{
+				[]interface{}{"commands", "+get", "keys", "~other:*", "channels", ""},
+			},
+		},
+	}
+	for name, reply := range replies {
+		cmd := &AclUserCmd{baseCmd: &baseCmd{}}
+		if err := cmd.ReadReply(reply); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got, _ := cmd.Result(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+func TestAclLogCmd(t *testing.T) {
+	clientInfo := "id=3 addr=127.0.0.1:52555 laddr=127.0.0.1:6379 fd=8 name= age=21 idle=0 flags=N db=0 user=billing"
+
+	replies := map[string]interface{}{
+		"resp2": []interface{}{
+			[]interface{}{
+				"count", int64(2),
+				"reason", "command",
+				"context", "toplevel",
+				"object", "keys",
+				"username", "billing",
+				"age-seconds", "1.5",
+				"client-info", clientInfo,
+				"entry-id", int64(7),
+				"timestamp-created", int64(1700000000000),
+				"timestamp-last-updated", int64(1700000001500),
+			},
+		},
+		"resp3": []interface{}{
+			map[interface{}]interface{}{
+				"count":                  int64(2),
+				"reason":                 "command",
+				"context":                "toplevel",
+				"object":                 "keys",
+				"username":               "billing",
+				"age-seconds":            1.5,
+				"client-info":            clientInfo,
+				"entry-id":               int64(7),
+				"timestamp-created":      int64(1700000000000),
+				"timestamp-last-updated": int64(1700000001500),
+			},
+		},
+	}
+	for name, reply := range replies {
+		cmd := &AclLogCmd{baseCmd: &baseCmd{}}
+		if err := cmd.ReadReply(reply); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		entries, _ := cmd.Result()
+		if len(entries) != 1 {
+			t.Fatalf("%s: got %d entries, want 1", name, len(entries))
+		}
+		e := entries[0]
+		if e.Count != 2 || e.Reason != "command" || e.Context != "toplevel" || e.Object != "keys" ||
+			e.Username != "billing" || e.Age != 1500*time.Millisecond || e.EntryID != 7 {
+			t.Fatalf("%s: got %+v", name, e)
+		}
+		if !e.TimestampCreated.Equal(time.UnixMilli(1700000000000)) || e.TimestampLastUpdated.Sub(e.TimestampCreated) != 1500*time.Millisecond {
+			t.Fatalf("%s: got %v, %v", name, e.TimestampCreated, e.TimestampLastUpdated)
+		}
+		if e.ClientInfo == nil || e.ClientInfo.ID != 3 || e.ClientInfo.User != "billing" {
+			t.Fatalf("%s: got client info %+v", name, e.ClientInfo)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package go_redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type BitmapCmdable interface {
+	SetBit(ctx context.Context, key string, offset int64, value int) *IntCmd
+	GetBit(ctx context.Context, key string, offset int64) *IntCmd
+	BitCount(ctx context.Context, key string, r *BitRange) *IntCmd
+	BitPos(ctx context.Context, key string, bit int64, r *BitRange) *IntCmd
+	BitOp(ctx context.Context, op, destKey string, keys ...string) *IntCmd
+	BitField(ctx context.Context, key string, ops *BitFieldOps) *BitFieldCmd
+	BitFieldRO(ctx context.Context, key string, ops *BitFieldOps) *BitFieldCmd
+}
+
+// BITOP operations. DIFF, DIFF1, ANDOR and ONE require Redis 8.2.
+const (
+	BitOpAnd   = "AND"
+	BitOpOr    = "OR"
+	BitOpXor   = "XOR"
+	BitOpNot   = "NOT"
+	BitOpDiff  = "DIFF"
+	BitOpDiff1 = "DIFF1"
+	BitOpAndOr = "ANDOR"
+	BitOpOne   = "ONE"
+)
+
+// BITFIELD overflow behaviours.
+const (
+	OverflowWrap = "WRAP"
+	OverflowSat  = "SAT"
+	OverflowFail = "FAIL"
+)
+
+// BitRange limits BITCOUNT and BITPOS to [Start, End].
+// Unit is BYTE (default) or BIT.
+type BitRange struct {
+	Start int64
+	End   int64
+	Unit  string
+}
+
+func (r *BitRange) appendArgs(args []interface{}) []interface{} {
+	if r == nil {
+		return args
+	}
+	args = append(args, r.Start, r.End)
+	if r.Unit != "" {
+		args = append(args, strings.ToLower(r.Unit))
+	}
+	return args
+}
+
+func (c cmdable) SetBit(ctx context.Context, key string, offset int64, value int) *IntCmd {
+	cmd := newIntCmd(ctx, "SETBIT", key, offset, value)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) GetBit(ctx context.Context, key string, offset int64) *IntCmd {
+	cmd := newIntCmd(ctx, "GETBIT", key, offset)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) BitCount(ctx context.Context, key string, r *BitRange) *IntCmd {
+	args := r.appendArgs([]interface{}{"BITCOUNT", key})
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) BitPos(ctx context.Context, key string, bit int64, r *BitRange) *IntCmd {
+	args := r.appendArgs([]interface{}{"BITPOS", key, bit})
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) BitOp(ctx context.Context, op, destKey string, keys ...string) *IntCmd {
+	args := make([]interface{}, 3, 3+len(keys))
+	args[0] = "BITOP"
+	args[1] = strings.ToLower(op)
+	args[2] = destKey
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// BitFieldOps collects BITFIELD sub-commands.
+//
+//	ops := NewBitFieldOps().
+//		Overflow(OverflowSat).
+//		IncrBy("u8", "#0", 1).
+//		Get("u8", "#0")
+//
+// offset is either a bit offset (int) or a "#N" type-multiplied offset.
+type BitFieldOps struct {
+	args []interface{}
+}
+
+func NewBitFieldOps() *BitFieldOps {
+	return &BitFieldOps{}
+}
+
+func (ops *BitFieldOps) Get(encoding string, offset interface{}) *BitFieldOps {
+	ops.args = append(ops.args, "get", encoding, offset)
+	return ops
+}
+
+func (ops *BitFieldOps) Set(encoding string, offset interface{}, value int64) *BitFieldOps {
+	ops.args = append(ops.args, "set", encoding, offset, value)
+	return ops
+}
+
+func (ops *BitFieldOps) IncrBy(encoding string, offset interface{}, increment int64) *BitFieldOps {
+	ops.args = append(ops.args, "incrby", encoding, offset, increment)
+	return ops
+}
+
+// Overflow applies to the SET and INCRBY operations that follow it.
+func (ops *BitFieldOps) Overflow(behavior string) *BitFieldOps {
+	ops.args = append(ops.args, "overflow", strings.ToLower(behavior))
+	return ops
+}
+
+func (c cmdable) BitField(ctx context.Context, key string, ops *BitFieldOps) *BitFieldCmd {
+	return c.bitField(ctx, "BITFIELD", key, ops)
+}
+
+// BitFieldRO only accepts GET operations.
+func (c cmdable) BitFieldRO(ctx context.Context, key string, ops *BitFieldOps) *BitFieldCmd {
+	return c.bitField(ctx, "BITFIELD_RO", key, ops)
+}
+
+func (c cmdable) bitField(ctx context.Context, name, key string, ops *BitFieldOps) *BitFieldCmd {
+	args := []interface{}{name, key}
+	if ops != nil {
+		args = append(args, ops.args...)
+	}
+	cmd := &BitFieldCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+	if ops == nil {
+		cmd.err = errors.New("redis: nil bitfield ops")
+		return cmd
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+type BitFieldCmd struct {
+	*baseCmd
+	// nil element: the operation was not performed due to OVERFLOW FAIL
+	result []*int64
+}
+
+func (cmd *BitFieldCmd) ReadReply(val interface{}) error {
+	vals, err := toSlice(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = make([]*int64, len(vals))
+	for i, v := range vals {
+		if v == nil {
+			continue
+		}
+		n, err := toInt64(v)
+		if err != nil {
+			return err
+		}
+		cmd.result[i] = &n
+	}
+	return nil
+}
+
+func (cmd *BitFieldCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *BitFieldCmd) Result() ([]*int64, error) {
+	return cmd.result, cmd.err
+}
@@ -0,0 +1,91 @@
+package go_redis
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestBitmapArgs(t *testing.T) {
+	var args []interface{}
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		args = cmd.Args()
+		return nil
+	})
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		cmd  func()
+		want []interface{}
+	}{
+		{func() { c.BitCount(ctx, "k", nil) }, []interface{}{"BITCOUNT", "k"}},
+		{func() { c.BitCount(ctx, "k", &BitRange{Start: 1, End: -1}) }, []interface{}{"BITCOUNT", "k", int64(1), int64(-1)}},
+		{func() { c.BitCount(ctx, "k", &BitRange{Start: 0, End: 7, Unit: "BYTE"}) }, []interface{}{"BITCOUNT", "k", int64(0), int64(7), "byte"}},
+		{func() { c.BitPos(ctx, "k", 1, &BitRange{Start: 2, End: 9, Unit: "BIT"}) }, []interface{}{"BITPOS", "k", int64(1), int64(2), int64(9), "bit"}},
+		{func() { c.BitOp(ctx, BitOpAndOr, "dst", "a", "b") }, []interface{}{"BITOP", "andor", "dst", "a", "b"}},
+		{func() {
+			c.BitField(ctx, "k", NewBitFieldOps().
+				Set("i8", 0, -1).
+				Overflow(OverflowSat).
+				IncrBy("u8", "#1", 300).
+				Overflow(OverflowFail).
+				IncrBy("u8", "#2", 1).
+				Get("u8", "#1"))
+		}, []interface{}{"BITFIELD", "k",
+			"set", "i8", 0, int64(-1),
+			"overflow", "sat", "incrby", "u8", "#1", int64(300),
+			"overflow", "fail", "incrby", "u8", "#2", int64(1),
+			"get", "u8", "#1"}},
+		{func() { c.BitFieldRO(ctx, "k", NewBitFieldOps().Get("u4", 4)) }, []interface{}{"BITFIELD_RO", "k", "get", "u4", 4}},
+	} {
+		tt.cmd()
+		if !reflect.DeepEqual(args, tt.want) {
+			t.Errorf("got %v, want %v", args, tt.want)
+		}
+	}
+
+	args = nil
+	if err := c.BitField(ctx, "k", nil).Err(); err == nil {
+		t.Fatal("nil ops accepted")
+	}
+	if err := c.BitFieldRO(ctx, "k", nil).Err(); err == nil {
+		t.Fatal("nil ops accepted")
+	}
+	if args != nil {
+		t.Fatalf("sent %v", args)
+	}
+}
+
+func TestBitFieldReply(t *testing.T) {
+	cmd := &BitFieldCmd{baseCmd: &baseCmd{}}
+	// the second INCRBY failed with OVERFLOW FAIL
+	if err := cmd.ReadReply([]interface{}{int64(255), nil, int64(3)}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := cmd.Result()
+	if len(got) != 3 || *got[0] != 255 || got[1] != nil || *got[2] != 3 {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestHyperLogLogArgs(t *testing.T) {
+	var args []interface{}
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		args = cmd.Args()
+		return nil
+	})
+	ctx := context.Background()
+
+	c.PFAdd(ctx, "hll", "a", 1)
+	if want := []interface{}{"PFADD", "hll", "a", 1}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+	c.PFCount(ctx, "h1", "h2")
+	if want := []interface{}{"PFCOUNT", "h1", "h2"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+	c.PFMerge(ctx, "dst", "h1", "h2")
+	if want := []interface{}{"PFMERGE", "dst", "h1", "h2"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+}
@@ -0,0 +1,236 @@
+// Package cache loads values through Redis (cache-aside) with protection
+// against stampedes: concurrent loads of a key are deduplicated in the
+// process and optionally across processes, and hot keys can be refreshed
+// before they expire.
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	go_redis "github.com/mingolm/go-redis"
+	"github.com/mingolm/go-redis/proto"
+	"math"
+	mrand "math/rand/v2"
+	"time"
+)
+
+// ErrNotFound is returned by loaders for missing values. It is cached for
+// Options.NegativeTTL and returned by Once.
+var ErrNotFound = errors.New("cache: not found")
+
+// errLocked stops a background refresh while another process loads.
+var errLocked = errors.New("cache: locked")
+
+// Cache loads values of type T:
+//
+//	users := cache.New[User](redis, &cache.Options{Beta: 1, StaleTTL: time.Minute})
+//	u, err := users.Once(ctx, "user:1", time.Hour, func(ctx context.Context) (User, error) {
+//		return db.User(ctx, 1)
+//	})
+type Cache[T any] struct {
+	rdb   go_redis.Cmdable
+	opt   *Options
+	group group[T]
+	// refreshes are separate from the loads of Once, which must not get
+	// the errLocked of a refresh
+	refreshes group[T]
+}
+
+func New[T any](rdb go_redis.Cmdable, opt *Options) *Cache[T] {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+
+	return &Cache[T]{
+		rdb: rdb,
+		opt: opt,
+	}
+}
+
+// Once returns the cached value of key, or the value from loader, which
+// is then cached for ttl. Errors of loader other than ErrNotFound are
+// returned and not cached; a value that can't be stored is still returned.
+func (c *Cache[T]) Once(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	e, err := c.get(ctx, key)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if e != nil {
+		now := time.Now()
+		if now.After(e.expiry) || c.refreshEarly(e, now) {
+			c.refresh(ctx, key, ttl, loader)
+		}
+		return c.decode(e)
+	}
+
+	return c.group.do(ctx, key, func() (T, error) {
+		return c.load(ctx, key, ttl, loader, true)
+	})
+}
+
+// Delete removes key, so the next Once loads it.
+func (c *Cache[T]) Delete(ctx context.Context, key string) error {
+	return c.rdb.Del(ctx, key).Err()
+}
+
+// get returns nil for a missing or unreadable entry.
+func (c *Cache[T]) get(ctx context.Context, key string) (*entry, error) {
+	b, err := c.rdb.GetBytes(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, proto.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	e, err := decodeEntry(b)
+	if err != nil {
+		// written by something else, overwritten by the next load
+		return nil, nil
+	}
+	return e, nil
+}
+
+func (c *Cache[T]) decode(e *entry) (val T, err error) {
+	if e.negative {
+		return val, ErrNotFound
+	}
+	err = c.opt.Codec.Unmarshal(e.data, &val)
+	return val, err
+}
+
+// refreshEarly is the XFetch decision: refresh when
+// now - delta * beta * ln(rand()) >= expiry.
+func (c *Cache[T]) refreshEarly(e *entry, now time.Time) bool {
+	if c.opt.Beta <= 0 || e.delta <= 0 {
+		return false
+	}
+	gap := -float64(e.delta) * c.opt.Beta * math.Log(1-mrand.Float64())
+	return !now.Add(time.Duration(gap)).Before(e.expiry)
+}
+
+// refresh reloads key in the background, unless a load is running.
+func (c *Cache[T]) refresh(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) {
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		_, _ = c.refreshes.do(ctx, key, func() (T, error) {
+			return c.load(ctx, key, ttl, loader, false)
+		})
+	}()
+}
+
+// load runs loader and stores its result. With a lock configured, a
+// caller not getting it waits for the value when wait is set, and
+// returns errLocked otherwise.
+func (c *Cache[T]) load(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error), wait bool) (T, error) {
+	if c.opt.LockTTL > 0 {
+		token, err := c.lock(ctx, key)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		if token == "" {
+			if !wait {
+				var zero T
+				return zero, errLocked
+			}
+			return c.wait(ctx, key, ttl, loader)
+		}
+		defer c.unlock(context.WithoutCancel(ctx), key, token)
+	}
+	return c.loadAndStore(ctx, key, ttl, loader)
+}
+
+func (c *Cache[T]) loadAndStore(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	start := time.Now()
+	val, err := loader(ctx)
+	delta := time.Since(start)
+
+	if err != nil {
+		if errors.Is(err, ErrNotFound) && c.opt.NegativeTTL > 0 {
+			ttl := c.jitter(c.opt.NegativeTTL)
+			e := &entry{negative: true, expiry: time.Now().Add(ttl), delta: delta}
+			_ = c.rdb.Set(ctx, key, e.encode(), ttl).Err()
+		}
+		return val, err
+	}
+
+	data, err := c.opt.Codec.Marshal(val)
+	if err != nil {
+		return val, err
+	}
+	ttl = c.jitter(ttl)
+	e := &entry{expiry: time.Now().Add(ttl), delta: delta, data: data}
+	_ = c.rdb.Set(ctx, key, e.encode(), ttl+c.opt.StaleTTL).Err()
+	return val, nil
+}
+
+// wait polls for the value loaded by another process, and loads it
+// without the lock once the lock expired.
+func (c *Cache[T]) wait(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	t := time.NewTicker(c.opt.LockPoll)
+	defer t.Stop()
+	deadline := time.Now().Add(c.opt.LockTTL)
+
+	for time.Now().Before(deadline) {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+
+		e, err := c.get(ctx, key)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		if e != nil {
+			return c.decode(e)
+		}
+	}
+	return c.loadAndStore(ctx, key, ttl, loader)
+}
+
+func (c *Cache[T]) jitter(ttl time.Duration) time.Duration {
+	if c.opt.Jitter <= 0 {
+		return ttl
+	}
+	f := 1 + c.opt.Jitter*(2*mrand.Float64()-1)
+	return time.Duration(float64(ttl) * f)
+}
+
+func lockKey(key string) string {
+	return key + ":lock"
+}
+
+// lock returns a token identifying the holder, empty if the lock is held.
+func (c *Cache[T]) lock(ctx context.Context, key string) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b[:])
+
+	ok, err := c.rdb.SetNX(ctx, lockKey(key), token, c.opt.LockTTL).Result()
+	if err != nil || !ok {
+		return "", err
+	}
+	return token, nil
+}
+
+// unlockScript deletes the lock only if still held with token, not after
+// it expired and was taken by another process.
+var unlockScript = go_redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func (c *Cache[T]) unlock(ctx context.Context, key, token string) {
+	_ = unlockScript.Run(ctx, c.rdb, []string{lockKey(key)}, token).Err()
+}
@@ -0,0 +1,207 @@
+package cache
+
+import (
+	"context"
+	"github.com/mingolm/go-redis/internal/redistest"
+	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEntry(t *testing.T) {
+	e := &entry{
+		negative: true,
+		expiry:   time.UnixMilli(1714564800123),
+		delta:    250 * time.Millisecond,
+		data:     []byte(`{"name":"mingo"}`),
+	}
+	got, err := decodeEntry(e.encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.negative || !got.expiry.Equal(e.expiry) || got.delta != e.delta || string(got.data) != string(e.data) {
+		t.Fatalf("got %+v, want %+v", got, e)
+	}
+
+	for _, b := range [][]byte{nil, {entryVersion}, {9, 0, 2, 2}, {entryVersion, 0, 0x80}} {
+		if _, err := decodeEntry(b); err == nil {
+			t.Fatalf("decode %v: want error", b)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var (
+		g       group[int]
+		calls   atomic.Int32
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := g.do(context.Background(), "k", func() (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, nil
+			})
+			if err != nil || v != 42 {
+				t.Errorf("got %d, %v", v, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("got %d calls, want 1", n)
+	}
+
+	// waiters give up with their context
+	block := make(chan struct{})
+	defer close(block)
+	go g.do(context.Background(), "slow", func() (int, error) {
+		<-block
+		return 0, nil
+	})
+	time.Sleep(10 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := g.do(ctx, "slow", nil); err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want deadline exceeded", err)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	c := New[int](nil, &Options{Jitter: 0.1})
+	for i := 0; i < 1000; i++ {
+		if ttl := c.jitter(time.Minute); ttl < 54*time.Second || ttl > 66*time.Second {
+			t.Fatalf("got %v, want within 10%% of 1m", ttl)
+		}
+	}
+	if ttl := New[int](nil, nil).jitter(time.Minute); ttl != time.Minute {
+		t.Fatalf("got %v without jitter", ttl)
+	}
+}
+
+func TestRefreshEarly(t *testing.T) {
+	now := time.Now()
+	far := &entry{expiry: now.Add(time.Hour), delta: time.Millisecond}
+	near := &entry{expiry: now.Add(time.Millisecond), delta: time.Second}
+
+	if New[int](nil, nil).refreshEarly(near, now) {
+		t.Fatal("refreshed with XFetch disabled")
+	}
+
+	c := New[int](nil, &Options{Beta: 1})
+	var farN, nearN int
+	for i := 0; i < 1000; i++ {
+		if c.refreshEarly(far, now) {
+			farN++
+		}
+		if c.refreshEarly(near, now) {
+			nearN++
+		}
+	}
+	// P(refresh) = exp(-remaining / (delta * beta))
+	if farN != 0 || nearN < 990 {
+		t.Fatalf("got %d refreshes far from expiry, %d near it", farN, nearN)
+	}
+}
+
+// fakeStore serves GET, SET [NX] and the unlock script from memory. The
+// first SET NX blocks until lockRelease is closed, and every SET NX finds
+// the lock held by another process.
+type fakeStore struct {
+	mu          sync.Mutex
+	data        map[string]string
+	locks       int
+	locking     chan struct{}
+	lockRelease chan struct{}
+}
+
+func (f *fakeStore) handle(cn net.Conn, args []string) {
+	switch args[0] {
+	case "GET":
+		f.mu.Lock()
+		val, ok := f.data[args[1]]
+		f.mu.Unlock()
+		if !ok {
+			redistest.Nil(cn)
+			return
+		}
+		redistest.Bulk(cn, val)
+	case "SET":
+		if slices.Contains(args, "nx") {
+			f.mu.Lock()
+			f.locks++
+			first := f.locks == 1
+			f.mu.Unlock()
+			if first {
+				close(f.locking)
+				<-f.lockRelease
+			}
+			redistest.Nil(cn)
+			return
+		}
+		f.mu.Lock()
+		f.data[args[1]] = args[2]
+		f.mu.Unlock()
+		redistest.Status(cn, "OK")
+	default:
+		redistest.Int(cn, 0)
+	}
+}
+
+func TestOnceDuringRefresh(t *testing.T) {
+	stale := &entry{expiry: time.Now().Add(-time.Second), data: []byte("1")}
+	f := &fakeStore{
+		data:        map[string]string{"k": string(stale.encode())},
+		locking:     make(chan struct{}),
+		lockRelease: make(chan struct{}),
+	}
+	c := New[int](redistest.NewClient(f.handle), &Options{
+		StaleTTL: time.Minute,
+		LockTTL:  50 * time.Millisecond,
+		LockPoll: 10 * time.Millisecond,
+	})
+	ctx := context.Background()
+	loader := func(ctx context.Context) (int, error) {
+		return 2, nil
+	}
+
+	// the stale value is served while the refresh waits for the lock
+	if v, err := c.Once(ctx, "k", time.Minute, loader); err != nil || v != 1 {
+		t.Fatalf("got %d, %v, want the stale value", v, err)
+	}
+	<-f.locking
+
+	// the entry is gone meanwhile, the refresh must not be joined
+	f.mu.Lock()
+	delete(f.data, "k")
+	f.mu.Unlock()
+	type result struct {
+		v   int
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		v, err := c.Once(ctx, "k", time.Minute, loader)
+		done <- result{v, err}
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(f.lockRelease)
+
+	select {
+	case r := <-done:
+		if r.err != nil || r.v != 2 {
+			t.Fatalf("got %d, %v, want the loaded value", r.v, r.err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("once blocked")
+	}
+}
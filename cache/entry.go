@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+const (
+	entryVersion  = 1
+	entryNegative = 1 << 0
+)
+
+var errBadEntry = errors.New("cache: malformed entry")
+
+// entry is the value stored in Redis: a header with the logical expiry and
+// the load duration XFetch needs, then the encoded value.
+//
+//	version | flags | expiry unix ms (varint) | delta ms (uvarint) | data
+type entry struct {
+	negative bool
+	expiry   time.Time
+	delta    time.Duration
+	data     []byte
+}
+
+func (e *entry) encode() []byte {
+	b := make([]byte, 0, 2+2*binary.MaxVarintLen64+len(e.data))
+	var flags byte
+	if e.negative {
+		flags |= entryNegative
+	}
+	b = append(b, entryVersion, flags)
+	b = binary.AppendVarint(b, e.expiry.UnixMilli())
+	b = binary.AppendUvarint(b, uint64(e.delta/time.Millisecond))
+	return append(b, e.data...)
+}
+
+func decodeEntry(b []byte) (*entry, error) {
+	if len(b) < 2 || b[0] != entryVersion {
+		return nil, errBadEntry
+	}
+	e := &entry{negative: b[1]&entryNegative != 0}
+	b = b[2:]
+
+	expiry, n := binary.Varint(b)
+	if n <= 0 {
+		return nil, errBadEntry
+	}
+	b = b[n:]
+	delta, n := binary.Uvarint(b)
+	if n <= 0 {
+		return nil, errBadEntry
+	}
+
+	e.expiry = time.UnixMilli(expiry)
+	e.delta = time.Duration(delta) * time.Millisecond
+	e.data = b[n:]
+	return e, nil
+}
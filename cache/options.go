@@ -0,0 +1,55 @@
+package cache
+
+import (
+	go_redis "github.com/mingolm/go-redis"
+	"time"
+)
+
+type Options struct {
+	// Codec encodes cached values.
+	// Default is go_redis.JSONCodec.
+	Codec go_redis.Codec
+
+	// Jitter spreads expirations over ±Jitter of the TTL, e.g. 0.1 for ±10%,
+	// so keys written together don't expire together.
+	// Default is no jitter.
+	Jitter float64
+
+	// Beta enables probabilistic early refresh (XFetch): a value is reloaded
+	// in the background with a probability rising as its expiry nears and
+	// with the time its load took. 1 is a good value, higher refreshes earlier.
+	// Default is 0, disabled.
+	Beta float64
+
+	// StaleTTL keeps values this long past their TTL. Stale values are
+	// returned while being reloaded in the background.
+	// Default is 0, expired values are reloaded synchronously.
+	StaleTTL time.Duration
+
+	// NegativeTTL caches ErrNotFound returned by loaders for this long.
+	// Default is 0, not found results are not cached.
+	NegativeTTL time.Duration
+
+	// LockTTL enables a Redis lock of this TTL deduplicating loads across
+	// processes. The other processes poll for the loaded value until the
+	// lock expires, then load it themselves.
+	// Default is 0, loads are only deduplicated within the process.
+	LockTTL time.Duration
+	// Polling interval while another process holds the lock.
+	// Default is 50 milliseconds.
+	LockPoll time.Duration
+}
+
+func (opt *Options) init() {
+	var (
+		codec    = go_redis.JSONCodec{}
+		lockPoll = time.Millisecond * 50
+	)
+
+	if opt.Codec == nil {
+		opt.Codec = codec
+	}
+	if opt.LockPoll == 0 {
+		opt.LockPoll = lockPoll
+	}
+}
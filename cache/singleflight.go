@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// group runs one call per key at a time, the concurrent callers of the
+// same key sharing its result.
+type group[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+type call[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// do runs fn unless a call for key is running, in which case it waits for
+// that call or ctx. The context of the first caller is the one fn runs with.
+func (g *group[T]) do(ctx context.Context, key string, fn func() (T, error)) (T, error) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.val, c.err
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*call[T])
+	}
+	c := &call[T]{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.val, c.err = fn()
+	return c.val, c.err
+}
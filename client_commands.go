@@ -0,0 +1,375 @@
+package go_redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ClientCmdable interface {
+	ClientList(ctx context.Context, opt *ClientListOptions) *ClientInfoSliceCmd
+	ClientInfo(ctx context.Context) *ClientInfoCmd
+	ClientKill(ctx context.Context, filter *ClientKillFilter) *IntCmd
+	ClientGetName(ctx context.Context) *StringCmd
+	ClientSetName(ctx context.Context, name string) *StatusCmd
+	ClientSetInfo(ctx context.Context, attr, value string) *StatusCmd
+	ClientID(ctx context.Context) *IntCmd
+	ClientPause(ctx context.Context, timeout time.Duration, mode string) *StatusCmd
+	ClientUnpause(ctx context.Context) *StatusCmd
+	ClientNoEvict(ctx context.Context, on bool) *StatusCmd
+	ClientNoTouch(ctx context.Context, on bool) *StatusCmd
+	ClientUnblock(ctx context.Context, id int64, withError bool) *IntCmd
+}
+
+// CLIENT PAUSE modes.
+const (
+	ClientPauseWrite = "WRITE"
+	ClientPauseAll   = "ALL"
+)
+
+type ClientListOptions struct {
+	// normal, master, replica or pubsub
+	Type string
+	IDs  []int64
+}
+
+// ClientKillFilter selects the connections to kill; zero fields are not
+// used as filters. At least one filter other than SkipMe is required, so
+// that an empty filter doesn't kill every other connection.
+type ClientKillFilter struct {
+	ID     int64
+	Type   string
+	User   string
+	Addr   string
+	LAddr  string
+	MaxAge time.Duration
+	// SkipMe defaults to yes on the server
+	SkipMe *bool
+}
+
+// ClientInfo is a connection as described by CLIENT LIST and CLIENT INFO.
+type ClientInfo struct {
+	ID            int64
+	Addr          string
+	LAddr         string
+	FD            int64
+	Name          string
+	Age           time.Duration
+	Idle          time.Duration
+	Flags         string
+	DB            int
+	Sub           int64
+	PSub          int64
+	SSub          int64
+	Multi         int64
+	Watch         int64
+	QueryBuf      int64
+	QueryBufFree  int64
+	ArgvMem       int64
+	MultiMem      int64
+	OutputBufLen  int64
+	OutputListLen int64
+	OutputMem     int64
+	TotalMem      int64
+	Events        string
+	LastCmd       string
+	User          string
+	Redir         int64
+	Resp          int
+	LibName       string
+	LibVer        string
+}
+
+func (c cmdable) ClientList(ctx context.Context, opt *ClientListOptions) *ClientInfoSliceCmd {
+	args := []interface{}{"CLIENT", "LIST"}
+	if opt != nil {
+		if opt.Type != "" {
+			args = append(args, "type", opt.Type)
+		}
+		if len(opt.IDs) > 0 {
+			args = append(args, "id")
+			for _, id := range opt.IDs {
+				args = append(args, id)
+			}
+		}
+	}
+	cmd := &ClientInfoSliceCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ClientInfo(ctx context.Context) *ClientInfoCmd {
+	cmd := &ClientInfoCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: []interface{}{"CLIENT", "INFO"},
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ClientKill(ctx context.Context, filter *ClientKillFilter) *IntCmd {
+	args := []interface{}{"CLIENT", "KILL"}
+	if filter == nil {
+		cmd := newIntCmd(ctx, args...)
+		cmd.err = errEmptyClientKillFilter
+		return cmd
+	}
+	if filter.ID > 0 {
+		args = append(args, "id", filter.ID)
+	}
+	if filter.Type != "" {
+		args = append(args, "type", filter.Type)
+	}
+	if filter.User != "" {
+		args = append(args, "user", filter.User)
+	}
+	if filter.Addr != "" {
+		args = append(args, "addr", filter.Addr)
+	}
+	if filter.LAddr != "" {
+		args = append(args, "laddr", filter.LAddr)
+	}
+	if filter.MaxAge > 0 {
+		args = append(args, "maxage", formatSec(ctx, filter.MaxAge))
+	}
+	if len(args) == 2 {
+		cmd := newIntCmd(ctx, args...)
+		cmd.err = errEmptyClientKillFilter
+		return cmd
+	}
+	if filter.SkipMe != nil {
+		if *filter.SkipMe {
+			args = append(args, "skipme", "yes")
+		} else {
+			args = append(args, "skipme", "no")
+		}
+	}
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+var errEmptyClientKillFilter = errors.New("redis: empty client kill filter")
+
+func (c cmdable) ClientGetName(ctx context.Context) *StringCmd {
+	cmd := newStringCmd(ctx, "CLIENT", "GETNAME")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ClientSetName(ctx context.Context, name string) *StatusCmd {
+	cmd := newStatusCmd(ctx, "CLIENT", "SETNAME", name)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// ClientSetInfo sets lib-name or lib-ver of the current connection.
+func (c cmdable) ClientSetInfo(ctx context.Context, attr, value string) *StatusCmd {
+	cmd := newStatusCmd(ctx, "CLIENT", "SETINFO", attr, value)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ClientID(ctx context.Context) *IntCmd {
+	cmd := newIntCmd(ctx, "CLIENT", "ID")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ClientPause(ctx context.Context, timeout time.Duration, mode string) *StatusCmd {
+	args := []interface{}{"CLIENT", "PAUSE", formatMs(ctx, timeout)}
+	if mode != "" {
+		args = append(args, strings.ToLower(mode))
+	}
+	cmd := newStatusCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ClientUnpause(ctx context.Context) *StatusCmd {
+	cmd := newStatusCmd(ctx, "CLIENT", "UNPAUSE")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ClientNoEvict(ctx context.Context, on bool) *StatusCmd {
+	cmd := newStatusCmd(ctx, "CLIENT", "NO-EVICT", onOff(on))
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ClientNoTouch(ctx context.Context, on bool) *StatusCmd {
+	cmd := newStatusCmd(ctx, "CLIENT", "NO-TOUCH", onOff(on))
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// ClientUnblock unblocks a client blocked in a blocking command. With
+// withError the blocked command fails with UNBLOCKED instead of timing out.
+func (c cmdable) ClientUnblock(ctx context.Context, id int64, withError bool) *IntCmd {
+	args := []interface{}{"CLIENT", "UNBLOCK", id}
+	if withError {
+		args = append(args, "error")
+	}
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+type ClientInfoCmd struct {
+	*baseCmd
+	result *ClientInfo
+}
+
+func (cmd *ClientInfoCmd) ReadReply(val interface{}) error {
+	s, err := toString(val)
+	if err != nil {
+		return err
+	}
+	cmd.result, err = parseClientInfo(strings.TrimSpace(s))
+	return err
+}
+
+func (cmd *ClientInfoCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *ClientInfoCmd) Result() (*ClientInfo, error) {
+	return cmd.result, cmd.err
+}
+
+type ClientInfoSliceCmd struct {
+	*baseCmd
+	result []*ClientInfo
+}
+
+func (cmd *ClientInfoSliceCmd) ReadReply(val interface{}) error {
+	s, err := toString(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = cmd.result[:0]
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		info, err := parseClientInfo(line)
+		if err != nil {
+			return err
+		}
+		cmd.result = append(cmd.result, info)
+	}
+	return nil
+}
+
+func (cmd *ClientInfoSliceCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *ClientInfoSliceCmd) Result() ([]*ClientInfo, error) {
+	return cmd.result, cmd.err
+}
+
+// parseClientInfo parses a single "id=3 addr=127.0.0.1:6379 ..." line.
+// Unknown fields are skipped so newer servers keep working.
+func parseClientInfo(line string) (*ClientInfo, error) {
+	info := &ClientInfo{}
+	for _, field := range strings.Fields(line) {
+		k, v, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("redis: unexpected client info field %q", field)
+		}
+
+		var err error
+		switch k {
+		case "id":
+			info.ID, err = strconv.ParseInt(v, 10, 64)
+		case "addr":
+			info.Addr = v
+		case "laddr":
+			info.LAddr = v
+		case "fd":
+			info.FD, err = strconv.ParseInt(v, 10, 64)
+		case "name":
+			info.Name = v
+		case "age":
+			info.Age, err = parseSeconds(v)
+		case "idle":
+			info.Idle, err = parseSeconds(v)
+		case "flags":
+			info.Flags = v
+		case "db":
+			info.DB, err = strconv.Atoi(v)
+		case "sub":
+			info.Sub, err = strconv.ParseInt(v, 10, 64)
+		case "psub":
+			info.PSub, err = strconv.ParseInt(v, 10, 64)
+		case "ssub":
+			info.SSub, err = strconv.ParseInt(v, 10, 64)
+		case "multi":
+			info.Multi, err = strconv.ParseInt(v, 10, 64)
+		case "watch":
+			info.Watch, err = strconv.ParseInt(v, 10, 64)
+		case "qbuf":
+			info.QueryBuf, err = strconv.ParseInt(v, 10, 64)
+		case "qbuf-free":
+			info.QueryBufFree, err = strconv.ParseInt(v, 10, 64)
+		case "argv-mem":
+			info.ArgvMem, err = strconv.ParseInt(v, 10, 64)
+		case "multi-mem":
+			info.MultiMem, err = strconv.ParseInt(v, 10, 64)
+		case "obl":
+			info.OutputBufLen, err = strconv.ParseInt(v, 10, 64)
+		case "oll":
+			info.OutputListLen, err = strconv.ParseInt(v, 10, 64)
+		case "omem":
+			info.OutputMem, err = strconv.ParseInt(v, 10, 64)
+		case "tot-mem":
+			info.TotalMem, err = strconv.ParseInt(v, 10, 64)
+		case "events":
+			info.Events = v
+		case "cmd":
+			info.LastCmd = v
+		case "user":
+			info.User = v
+		case "redir":
+			info.Redir, err = strconv.ParseInt(v, 10, 64)
+		case "resp":
+			info.Resp, err = strconv.Atoi(v)
+		case "lib-name":
+			info.LibName = v
+		case "lib-ver":
+			info.LibVer = v
+		}
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid client info field %q: %w", field, err)
+		}
+	}
+	return info, nil
+}
+
+func parseSeconds(s string) (time.Duration, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(n) * time.Second, nil
+}
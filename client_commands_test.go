@@ -0,0 +1,62 @@
+package go_redis
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClientInfoSliceCmd(t *testing.T) {
+	reply := "id=3 addr=127.0.0.1:52555 laddr=127.0.0.1:6379 fd=8 name=billing age=21 idle=0 " +
+		"flags=N db=2 sub=0 psub=0 ssub=0 multi=-1 watch=0 qbuf=26 qbuf-free=20448 argv-mem=10 " +
+		"multi-mem=0 obl=0 oll=0 omem=0 tot-mem=40986 events=r cmd=client|list user=default " +
+		"redir=-1 resp=3 lib-name=mingolm-go-redis lib-ver=0.1.0 io-thread=0\n" +
+		"id=4 addr=127.0.0.1:52556 laddr=127.0.0.1:6379 fd=9 name= age=5 idle=5 flags=N db=0\n"
+
+	cmd := &ClientInfoSliceCmd{baseCmd: &baseCmd{}}
+	if err := cmd.ReadReply(reply); err != nil {
+		t.Fatal(err)
+	}
+	clients, _ := cmd.Result()
+	if len(clients) != 2 {
+		t.Fatalf("got %d clients, want 2", len(clients))
+	}
+
+	c := clients[0]
+	if c.ID != 3 || c.Name != "billing" || c.DB != 2 || c.Age != 21*time.Second {
+		t.Fatalf("got %+v", c)
+	}
+	if c.LastCmd != "client|list" || c.Resp != 3 || c.LibName != "mingolm-go-redis" || c.Multi != -1 {
+		t.Fatalf("got %+v", c)
+	}
+	if clients[1].Name != "" || clients[1].Idle != 5*time.Second {
+		t.Fatalf("got %+v", clients[1])
+	}
+}
+
+func TestClientKill(t *testing.T) {
+	var args []interface{}
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		args = cmd.Args()
+		return nil
+	})
+	ctx := context.Background()
+
+	no := false
+	c.ClientKill(ctx, &ClientKillFilter{User: "app", MaxAge: time.Minute, SkipMe: &no})
+	if want := []interface{}{"CLIENT", "KILL", "user", "app", "maxage", int64(60), "skipme", "no"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+
+	args = nil
+	yes := true
+	for _, filter := range []*ClientKillFilter{nil, {}, {SkipMe: &yes}} {
+		if err := c.ClientKill(ctx, filter).Err(); err == nil {
+			t.Fatalf("filter %+v accepted", filter)
+		}
+	}
+	if args != nil {
+		t.Fatalf("sent %v", args)
+	}
+}
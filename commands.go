@@ -2,16 +2,44 @@ package go_redis
 
 import (
 	"context"
+	"fmt"
+	"github.com/mingolm/go-redis/proto"
+	"io"
+	"strings"
 	"time"
 )
 
 type Cmdable interface {
 	Set(ctx context.Context, key string, val any, expiration time.Duration) *StatusCmd
 	Get(ctx context.Context, key string) *StringCmd
+	GetBytes(ctx context.Context, key string) *BytesCmd
+	GetToWriter(ctx context.Context, key string, w io.Writer) *IntCmd
+	SetFromReader(ctx context.Context, key string, r io.Reader, size int64, expiration time.Duration) *StatusCmd
+	MSet(ctx context.Context, values ...interface{}) *StatusCmd
+	MGet(ctx context.Context, keys ...string) *SliceCmd
+	SetNX(ctx context.Context, key string, val any, expiration time.Duration) *BoolCmd
+
+	KeyCmdable
+	HashCmdable
+	ListCmdable
+	SortedSetCmdable
+	GeoCmdable
+	BitmapCmdable
+	HyperLogLogCmdable
+	ScanCmdable
+	ServerCmdable
+	ClientCmdable
+	AclCmdable
+	DiagnosticCmdable
+	FunctionCmdable
+	ScriptingCmdable
+	PubSubCmdable
 }
 
 type cmdable func(ctx context.Context, cmd Cmder) error
 
+var _ Cmdable = cmdable(nil)
+
 func (c cmdable) Set(ctx context.Context, key string, val any, expiration time.Duration) *StatusCmd {
 	args := make([]interface{}, 3, 5)
 	args[0] = "SET"
@@ -34,6 +62,29 @@ func (c cmdable) Set(ctx context.Context, key string, val any, expiration time.D
 	return cmd
 }
 
+// SetNX sets key only if it does not exist, and reports whether it did.
+func (c cmdable) SetNX(ctx context.Context, key string, val any, expiration time.Duration) *BoolCmd {
+	args := make([]interface{}, 4, 6)
+	args[0] = "SET"
+	args[1] = key
+	args[2] = val
+	args[3] = "nx"
+	if expiration > 0 {
+		if usePrecise(expiration) {
+			args = append(args, "px", formatMs(ctx, expiration))
+		} else {
+			args = append(args, "ex", formatSec(ctx, expiration))
+		}
+	}
+	cmd := newBoolCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	if cmd.err == proto.Nil {
+		// not set
+		cmd.err = nil
+	}
+	return cmd
+}
+
 func (c cmdable) Get(ctx context.Context, key string) *StringCmd {
 	cmd := &StringCmd{
 		baseCmd: &baseCmd{
@@ -45,6 +96,70 @@ func (c cmdable) Get(ctx context.Context, key string) *StringCmd {
 	return cmd
 }
 
+// MSet sets several keys at once. values are flattened with
+// proto.AppendArgs:
+//
+//	MSet(ctx, "k1", "v1", "k2", "v2")
+//	MSet(ctx, []string{"k1", "v1", "k2", "v2"})
+//	MSet(ctx, map[string]interface{}{"k1": "v1", "k2": 2})
+func (c cmdable) MSet(ctx context.Context, values ...interface{}) *StatusCmd {
+	args := make([]interface{}, 1, 1+2*len(values))
+	args[0] = "MSET"
+	args = proto.AppendArgs(args, values...)
+	cmd := newStatusCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// MGet returns the values of keys, nil for the missing ones.
+func (c cmdable) MGet(ctx context.Context, keys ...string) *SliceCmd {
+	cmd := newSliceCmd(ctx, keysArgs("MGET", keys)...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// SetFromReader is Set with a value of size bytes streamed from r, so it
+// is never held in memory as a whole. The command is not retried, r
+// having been consumed.
+func (c cmdable) SetFromReader(ctx context.Context, key string, r io.Reader, size int64, expiration time.Duration) *StatusCmd {
+	args := make([]interface{}, 3, 5)
+	args[0] = "SET"
+	args[1] = key
+	args[2] = &proto.BulkReader{R: r, Size: size}
+	if expiration > 0 {
+		if usePrecise(expiration) {
+			args = append(args, "px", formatMs(ctx, expiration))
+		} else {
+			args = append(args, "ex", formatSec(ctx, expiration))
+		}
+	}
+	cmd := &streamStatusCmd{
+		StatusCmd: newStatusCmd(ctx, args...),
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd.StatusCmd
+}
+
+// GetToWriter copies the value of key to w as it is read from the
+// connection and returns the number of bytes written. The command is not
+// retried once the copy has started.
+func (c cmdable) GetToWriter(ctx context.Context, key string, w io.Writer) *IntCmd {
+	cmd := &copyCmd{
+		IntCmd: newIntCmd(ctx, "GET", key),
+		w:      w,
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd.IntCmd
+}
+
+// GetBytes is Get for binary values, the payload is read without
+// being copied through a string.
+func (c cmdable) GetBytes(ctx context.Context, key string) *BytesCmd {
+	cmd := newBytesCmd(ctx, "GET", key)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
 type Cmder interface {
 	Err() error
 	Args() []interface{}
@@ -52,6 +167,13 @@ type Cmder interface {
 	String() string
 }
 
+// RESPReader is implemented by commands that decode their reply straight
+// from the connection with the typed readers of proto.RESP, skipping the
+// interface{} values passed to ReadReply.
+type RESPReader interface {
+	ReadRESP(rd *proto.RESP) error
+}
+
 type baseCmd struct {
 	ctx  context.Context
 	args []interface{}
@@ -71,9 +193,23 @@ type StatusCmd struct {
 	result string
 }
 
-func (cmd *StatusCmd) ReadReply(val interface{}) error {
-	cmd.result = val.(string)
-	return nil
+func newStatusCmd(ctx context.Context, args ...interface{}) *StatusCmd {
+	return &StatusCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *StatusCmd) ReadReply(val interface{}) (err error) {
+	cmd.result, err = toString(val)
+	return err
+}
+
+func (cmd *StatusCmd) ReadRESP(rd *proto.RESP) (err error) {
+	cmd.result, err = rd.ReadString()
+	return err
 }
 
 func (cmd *StatusCmd) String() string {
@@ -89,9 +225,23 @@ type StringCmd struct {
 	result string
 }
 
-func (cmd *StringCmd) ReadReply(val interface{}) error {
-	cmd.result = val.(string)
-	return nil
+func newStringCmd(ctx context.Context, args ...interface{}) *StringCmd {
+	return &StringCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *StringCmd) ReadReply(val interface{}) (err error) {
+	cmd.result, err = toString(val)
+	return err
+}
+
+func (cmd *StringCmd) ReadRESP(rd *proto.RESP) (err error) {
+	cmd.result, err = rd.ReadString()
+	return err
 }
 
 func (cmd *StringCmd) String() string {
@@ -101,3 +251,560 @@ func (cmd *StringCmd) String() string {
 func (cmd *StringCmd) Result() (string, error) {
 	return cmd.result, cmd.err
 }
+
+// Scan parses the value into dst, see scanValue for the supported types.
+func (cmd *StringCmd) Scan(dst interface{}) error {
+	if cmd.err != nil {
+		return cmd.err
+	}
+	return scanValue(cmd.result, dst)
+}
+
+// The conversion helpers below parse the value in the format the argument
+// writer uses, so values written with Set read back unchanged.
+
+func (cmd *StringCmd) Bytes() ([]byte, error) {
+	if cmd.err != nil {
+		return nil, cmd.err
+	}
+	return []byte(cmd.result), nil
+}
+
+func (cmd *StringCmd) Int() (int, error) {
+	var n int
+	err := cmd.Scan(&n)
+	return n, err
+}
+
+func (cmd *StringCmd) Int64() (int64, error) {
+	var n int64
+	err := cmd.Scan(&n)
+	return n, err
+}
+
+func (cmd *StringCmd) Uint64() (uint64, error) {
+	var n uint64
+	err := cmd.Scan(&n)
+	return n, err
+}
+
+func (cmd *StringCmd) Float32() (float32, error) {
+	var f float32
+	err := cmd.Scan(&f)
+	return f, err
+}
+
+func (cmd *StringCmd) Float64() (float64, error) {
+	var f float64
+	err := cmd.Scan(&f)
+	return f, err
+}
+
+// Bool accepts 1/0 as written for bool arguments, and true/false.
+func (cmd *StringCmd) Bool() (bool, error) {
+	var b bool
+	err := cmd.Scan(&b)
+	return b, err
+}
+
+// Time parses an RFC3339Nano value as written for time.Time arguments.
+func (cmd *StringCmd) Time() (time.Time, error) {
+	var t time.Time
+	err := cmd.Scan(&t)
+	return t, err
+}
+
+type BytesCmd struct {
+	*baseCmd
+	result []byte
+}
+
+func newBytesCmd(ctx context.Context, args ...interface{}) *BytesCmd {
+	return &BytesCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *BytesCmd) ReadReply(val interface{}) error {
+	s, err := toString(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = []byte(s)
+	return nil
+}
+
+func (cmd *BytesCmd) ReadRESP(rd *proto.RESP) (err error) {
+	cmd.result, err = rd.ReadBytes()
+	return err
+}
+
+func (cmd *BytesCmd) String() string {
+	return string(cmd.result)
+}
+
+func (cmd *BytesCmd) Result() ([]byte, error) {
+	return cmd.result, cmd.err
+}
+
+// nonRetryable is implemented by commands which can't be replayed, e.g.
+// streaming from an io.Reader or to an io.Writer.
+type nonRetryable interface {
+	nonRetryable()
+}
+
+// copyCmd streams a bulk reply to w, its result is the size of the copy.
+type copyCmd struct {
+	*IntCmd
+	w io.Writer
+}
+
+func (cmd *copyCmd) nonRetryable() {}
+
+func (cmd *copyCmd) ReadReply(val interface{}) error {
+	s, err := toString(val)
+	if err != nil {
+		return err
+	}
+	n, err := io.WriteString(cmd.w, s)
+	cmd.result = int64(n)
+	return err
+}
+
+func (cmd *copyCmd) ReadRESP(rd *proto.RESP) (err error) {
+	cmd.result, err = rd.ReadBulkTo(cmd.w)
+	return err
+}
+
+type streamStatusCmd struct {
+	*StatusCmd
+}
+
+func (cmd *streamStatusCmd) nonRetryable() {}
+
+type IntCmd struct {
+	*baseCmd
+	result int64
+}
+
+func newIntCmd(ctx context.Context, args ...interface{}) *IntCmd {
+	return &IntCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *IntCmd) ReadReply(val interface{}) (err error) {
+	cmd.result, err = toInt64(val)
+	return err
+}
+
+func (cmd *IntCmd) ReadRESP(rd *proto.RESP) (err error) {
+	cmd.result, err = rd.ReadInt()
+	return err
+}
+
+func (cmd *IntCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *IntCmd) Result() (int64, error) {
+	return cmd.result, cmd.err
+}
+
+// BoolCmd holds a reply that is OK/nil, 1/0 or a RESP3 boolean.
+type BoolCmd struct {
+	*baseCmd
+	result bool
+}
+
+func newBoolCmd(ctx context.Context, args ...interface{}) *BoolCmd {
+	return &BoolCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *BoolCmd) ReadReply(val interface{}) error {
+	switch v := val.(type) {
+	case nil:
+		cmd.result = false
+	case bool:
+		cmd.result = v
+	case int64:
+		cmd.result = v != 0
+	case string:
+		cmd.result = v == "OK"
+	default:
+		return fmt.Errorf("redis: unexpected reply type %T, want bool", val)
+	}
+	return nil
+}
+
+func (cmd *BoolCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *BoolCmd) Result() (bool, error) {
+	return cmd.result, cmd.err
+}
+
+type FloatCmd struct {
+	*baseCmd
+	result float64
+}
+
+func newFloatCmd(ctx context.Context, args ...interface{}) *FloatCmd {
+	return &FloatCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *FloatCmd) ReadReply(val interface{}) (err error) {
+	cmd.result, err = toFloat64(val)
+	return err
+}
+
+func (cmd *FloatCmd) ReadRESP(rd *proto.RESP) (err error) {
+	cmd.result, err = rd.ReadFloat()
+	return err
+}
+
+func (cmd *FloatCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *FloatCmd) Result() (float64, error) {
+	return cmd.result, cmd.err
+}
+
+type StringSliceCmd struct {
+	*baseCmd
+	result []string
+}
+
+func newStringSliceCmd(ctx context.Context, args ...interface{}) *StringSliceCmd {
+	return &StringSliceCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *StringSliceCmd) ReadReply(val interface{}) error {
+	vals, err := toSlice(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = make([]string, len(vals))
+	for i, v := range vals {
+		// nil element (e.g. GEOHASH of a missing member) is kept as ""
+		if v == nil {
+			continue
+		}
+		if cmd.result[i], err = toString(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cmd *StringSliceCmd) ReadRESP(rd *proto.RESP) error {
+	n, err := rd.ReadArrayLen()
+	if err != nil {
+		return err
+	}
+	cmd.result = make([]string, n)
+	for i := range cmd.result {
+		s, err := rd.ReadString()
+		if err == proto.Nil {
+			continue
+		}
+		if err != nil {
+			return discardElems(rd, n-i-1, err)
+		}
+		cmd.result[i] = s
+	}
+	return nil
+}
+
+func (cmd *StringSliceCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *StringSliceCmd) Result() ([]string, error) {
+	return cmd.result, cmd.err
+}
+
+type MapStringStringCmd struct {
+	*baseCmd
+	result map[string]string
+}
+
+func newMapStringStringCmd(ctx context.Context, args ...interface{}) *MapStringStringCmd {
+	return &MapStringStringCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+// ReadReply accepts both a resp3 map and a resp2 flat key/value array.
+func (cmd *MapStringStringCmd) ReadReply(val interface{}) (err error) {
+	cmd.result, err = toStringMap(val)
+	return err
+}
+
+func (cmd *MapStringStringCmd) ReadRESP(rd *proto.RESP) error {
+	n, err := rd.ReadMapLen()
+	if err != nil {
+		return err
+	}
+	cmd.result = make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		k, err := rd.ReadString()
+		if err != nil {
+			return discardElems(rd, 2*(n-i)-1, err)
+		}
+		v, err := rd.ReadString()
+		if err != nil && err != proto.Nil {
+			return discardElems(rd, 2*(n-i-1), err)
+		}
+		cmd.result[k] = v
+	}
+	return nil
+}
+
+func (cmd *MapStringStringCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *MapStringStringCmd) Result() (map[string]string, error) {
+	return cmd.result, cmd.err
+}
+
+// Scan sets the fields of the struct pointed to by dst whose
+// `redis:"name"` tag matches a key of the reply.
+func (cmd *MapStringStringCmd) Scan(dst interface{}) error {
+	if cmd.err != nil {
+		return cmd.err
+	}
+	ss, err := newStructScanner(dst)
+	if err != nil {
+		return err
+	}
+	for k, v := range cmd.result {
+		if err := ss.scan(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SliceCmd holds an array reply of mixed values, e.g. of MGET and HMGET
+// where missing keys are nil.
+type SliceCmd struct {
+	*baseCmd
+	result []interface{}
+}
+
+func newSliceCmd(ctx context.Context, args ...interface{}) *SliceCmd {
+	return &SliceCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *SliceCmd) ReadReply(val interface{}) (err error) {
+	cmd.result, err = toSlice(val)
+	return err
+}
+
+// ReadRESP reads the bulk strings and nils replied by MGET and HMGET.
+func (cmd *SliceCmd) ReadRESP(rd *proto.RESP) error {
+	n, err := rd.ReadArrayLen()
+	if err != nil {
+		return err
+	}
+	cmd.result = make([]interface{}, n)
+	for i := range cmd.result {
+		s, err := rd.ReadString()
+		if err == proto.Nil {
+			continue
+		}
+		if err != nil {
+			return discardElems(rd, n-i-1, err)
+		}
+		cmd.result[i] = s
+	}
+	return nil
+}
+
+func (cmd *SliceCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *SliceCmd) Result() ([]interface{}, error) {
+	return cmd.result, cmd.err
+}
+
+// Scan sets the fields of the struct pointed to by dst from the reply of
+// MGET or HMGET, matching the requested keys or fields with the
+// `redis:"name"` tags. nil values are skipped.
+func (cmd *SliceCmd) Scan(dst interface{}) error {
+	if cmd.err != nil {
+		return cmd.err
+	}
+	ss, err := newStructScanner(dst)
+	if err != nil {
+		return err
+	}
+
+	// names follow the command (MGET) or the key (HMGET)
+	names := cmd.args[1:]
+	if name, _ := cmd.args[0].(string); strings.EqualFold(name, "HMGET") {
+		names = cmd.args[2:]
+	}
+	if len(names) != len(cmd.result) {
+		return fmt.Errorf("redis: got %d values for %d names", len(cmd.result), len(names))
+	}
+
+	for i, v := range cmd.result {
+		if v == nil {
+			continue
+		}
+		name, ok := names[i].(string)
+		if !ok {
+			continue
+		}
+		s, err := toString(v)
+		if err != nil {
+			return err
+		}
+		if err := ss.scan(name, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cmd holds a reply of any shape, e.g. of FCALL or EVAL.
+type Cmd struct {
+	*baseCmd
+	result interface{}
+}
+
+func newCmd(ctx context.Context, args ...interface{}) *Cmd {
+	return &Cmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *Cmd) ReadReply(val interface{}) error {
+	cmd.result = val
+	return nil
+}
+
+func (cmd *Cmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *Cmd) Result() (interface{}, error) {
+	return cmd.result, cmd.err
+}
+
+func (cmd *Cmd) Text() (string, error) {
+	if cmd.err != nil {
+		return "", cmd.err
+	}
+	return toString(cmd.result)
+}
+
+func (cmd *Cmd) Int64() (int64, error) {
+	if cmd.err != nil {
+		return 0, cmd.err
+	}
+	return toInt64(cmd.result)
+}
+
+func (cmd *Cmd) Float64() (float64, error) {
+	if cmd.err != nil {
+		return 0, cmd.err
+	}
+	return toFloat64(cmd.result)
+}
+
+// Bool reports integer replies as n != 0, as returned by Lua booleans.
+func (cmd *Cmd) Bool() (bool, error) {
+	if cmd.err != nil {
+		return false, cmd.err
+	}
+	switch v := cmd.result.(type) {
+	case bool:
+		return v, nil
+	case nil:
+		return false, nil
+	default:
+		n, err := toInt64(v)
+		return n != 0, err
+	}
+}
+
+func (cmd *Cmd) Slice() ([]interface{}, error) {
+	if cmd.err != nil {
+		return nil, cmd.err
+	}
+	return toSlice(cmd.result)
+}
+
+func (cmd *Cmd) StringSlice() ([]string, error) {
+	if cmd.err != nil {
+		return nil, cmd.err
+	}
+	return toStrings(cmd.result)
+}
+
+func (cmd *Cmd) Int64Slice() ([]int64, error) {
+	if cmd.err != nil {
+		return nil, cmd.err
+	}
+	vals, err := toSlice(cmd.result)
+	if err != nil {
+		return nil, err
+	}
+	ns := make([]int64, len(vals))
+	for i, v := range vals {
+		if ns[i], err = toInt64(v); err != nil {
+			return nil, err
+		}
+	}
+	return ns, nil
+}
+
+// discardElems reads the n elements left of an aggregate reply after one
+// of them failed with err, so that the connection stays in sync.
+func discardElems(rd *proto.RESP, n int, err error) error {
+	for ; n > 0; n-- {
+		if _, derr := rd.Read(); isBadConn(derr) {
+			return derr
+		}
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+package go_redis
+
+import (
+	"bufio"
+	"github.com/mingolm/go-redis/proto"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readRESP reads reply into cmd with the typed readers, and checks that
+// the reply was consumed up to the "+NEXT" status following it.
+func readRESP(t *testing.T, cmd RESPReader, reply string) error {
+	t.Helper()
+	rd := proto.NewReader(bufio.NewReader(strings.NewReader(reply + "+NEXT\r\n")))
+	err := cmd.ReadRESP(rd)
+	if next, nerr := rd.ReadString(); nerr != nil || next != "NEXT" {
+		t.Fatalf("reply not consumed: got %q, %v", next, nerr)
+	}
+	return err
+}
+
+func TestAggregateReadRESP(t *testing.T) {
+	ss := &StringSliceCmd{baseCmd: &baseCmd{}}
+	if err := readRESP(t, ss, "*4\r\n$1\r\na\r\n$-1\r\n:3\r\n+ok\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "", "3", "ok"}; !reflect.DeepEqual(ss.result, want) {
+		t.Fatalf("got %q, want %q", ss.result, want)
+	}
+	if err := readRESP(t, ss, "*-1\r\n"); err != proto.Nil {
+		t.Fatalf("got %v, want Nil", err)
+	}
+	// a failing element doesn't leave the rest of the reply unread
+	if err := readRESP(t, ss, "*3\r\n$1\r\na\r\n-ERR oops\r\n$1\r\nc\r\n"); !proto.IsRedisError(err) {
+		t.Fatalf("got %v, want the error element", err)
+	}
+
+	sl := &SliceCmd{baseCmd: &baseCmd{}}
+	if err := readRESP(t, sl, "*2\r\n$-1\r\n$1\r\nb\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{nil, "b"}; !reflect.DeepEqual(sl.result, want) {
+		t.Fatalf("got %v, want %v", sl.result, want)
+	}
+
+	want := map[string]string{"name": "mingo", "age": "18"}
+	for name, reply := range map[string]string{
+		"resp2": "*4\r\n$4\r\nname\r\n$5\r\nmingo\r\n$3\r\nage\r\n$2\r\n18\r\n",
+		"resp3": "%2\r\n$4\r\nname\r\n$5\r\nmingo\r\n$3\r\nage\r\n:18\r\n",
+	} {
+		m := &MapStringStringCmd{baseCmd: &baseCmd{}}
+		if err := readRESP(t, m, reply); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(m.result, want) {
+			t.Fatalf("%s: got %v, want %v", name, m.result, want)
+		}
+	}
+	m := &MapStringStringCmd{baseCmd: &baseCmd{}}
+	if err := readRESP(t, m, "%2\r\n$1\r\na\r\n*1\r\n:1\r\n$1\r\nb\r\n$1\r\nc\r\n"); err == nil {
+		t.Fatal("want error for a nested value")
+	}
+}
+
+// BenchmarkReadStringSlice compares reading an LRANGE reply through
+// interface{} values with the typed readers.
+func BenchmarkReadStringSlice(b *testing.B) {
+	var sb strings.Builder
+	sb.WriteString("*16\r\n")
+	for i := 0; i < 16; i++ {
+		sb.WriteString("$8\r\nabcdefgh\r\n")
+	}
+	reply := sb.String()
+
+	bench := func(b *testing.B, read func(rd *proto.RESP, cmd *StringSliceCmd) error) {
+		sr := strings.NewReader(reply)
+		br := bufio.NewReader(sr)
+		cmd := &StringSliceCmd{baseCmd: &baseCmd{}}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sr.Reset(reply)
+			br.Reset(sr)
+			rd := proto.AcquireReader(br)
+			err := read(rd, cmd)
+			proto.Release(rd)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.Run("ReadReply", func(b *testing.B) {
+		bench(b, func(rd *proto.RESP, cmd *StringSliceCmd) error {
+			val, err := rd.Read()
+			if err != nil {
+				return err
+			}
+			return cmd.ReadReply(val)
+		})
+	})
+	b.Run("ReadRESP", func(b *testing.B) {
+		bench(b, func(rd *proto.RESP, cmd *StringSliceCmd) error {
+			return cmd.ReadRESP(rd)
+		})
+	})
+}
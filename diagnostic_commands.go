@@ -0,0 +1,518 @@
+package go_redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type DiagnosticCmdable interface {
+	SlowLogGet(ctx context.Context, count int64) *SlowLogCmd
+	SlowLogLen(ctx context.Context) *IntCmd
+	SlowLogReset(ctx context.Context) *StatusCmd
+	LatencyLatest(ctx context.Context) *LatencyLatestCmd
+	LatencyHistory(ctx context.Context, event string) *LatencyHistoryCmd
+	LatencyHistogram(ctx context.Context, commands ...string) *LatencyHistogramCmd
+	LatencyReset(ctx context.Context, events ...string) *IntCmd
+	MemoryUsage(ctx context.Context, key string, samples int) *IntCmd
+	MemoryStats(ctx context.Context) *MemoryStatsCmd
+	MemoryDoctor(ctx context.Context) *StringCmd
+}
+
+// SlowLogGet returns the most recent slow log entries, at most count if
+// > 0 (server default is 10), or all of them if count is -1.
+func (c cmdable) SlowLogGet(ctx context.Context, count int64) *SlowLogCmd {
+	args := []interface{}{"SLOWLOG", "GET"}
+	if count != 0 {
+		args = append(args, count)
+	}
+	cmd := &SlowLogCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) SlowLogLen(ctx context.Context) *IntCmd {
+	cmd := newIntCmd(ctx, "SLOWLOG", "LEN")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) SlowLogReset(ctx context.Context) *StatusCmd {
+	cmd := newStatusCmd(ctx, "SLOWLOG", "RESET")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) LatencyLatest(ctx context.Context) *LatencyLatestCmd {
+	cmd := &LatencyLatestCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: []interface{}{"LATENCY", "LATEST"},
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) LatencyHistory(ctx context.Context, event string) *LatencyHistoryCmd {
+	cmd := &LatencyHistoryCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: []interface{}{"LATENCY", "HISTORY", event},
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// LatencyHistogram returns the latency distribution of commands, or of
+// every command that was called if none are given.
+func (c cmdable) LatencyHistogram(ctx context.Context, commands ...string) *LatencyHistogramCmd {
+	args := make([]interface{}, 2, 2+len(commands))
+	args[0] = "LATENCY"
+	args[1] = "HISTOGRAM"
+	for _, command := range commands {
+		args = append(args, command)
+	}
+	cmd := &LatencyHistogramCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) LatencyReset(ctx context.Context, events ...string) *IntCmd {
+	args := make([]interface{}, 2, 2+len(events))
+	args[0] = "LATENCY"
+	args[1] = "RESET"
+	for _, event := range events {
+		args = append(args, event)
+	}
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// MemoryUsage reports the bytes used by key. Nested values are sampled,
+// samples 0 uses the server default and -1 samples all of them.
+func (c cmdable) MemoryUsage(ctx context.Context, key string, samples int) *IntCmd {
+	args := []interface{}{"MEMORY", "USAGE", key}
+	if samples < 0 {
+		args = append(args, "samples", 0)
+	} else if samples > 0 {
+		args = append(args, "samples", samples)
+	}
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) MemoryStats(ctx context.Context) *MemoryStatsCmd {
+	cmd := &MemoryStatsCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: []interface{}{"MEMORY", "STATS"},
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) MemoryDoctor(ctx context.Context) *StringCmd {
+	cmd := newStringCmd(ctx, "MEMORY", "DOCTOR")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+type SlowLog struct {
+	ID         int64
+	Time       time.Time
+	Duration   time.Duration
+	Args       []string
+	ClientAddr string
+	ClientName string
+}
+
+type SlowLogCmd struct {
+	*baseCmd
+	result []SlowLog
+}
+
+func (cmd *SlowLogCmd) ReadReply(val interface{}) error {
+	entries, err := toSlice(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = make([]SlowLog, len(entries))
+	for i, e := range entries {
+		fields, err := toSlice(e)
+		if err != nil {
+			return err
+		}
+		// client addr and name were added in Redis 4.0
+		if len(fields) < 4 {
+			return fmt.Errorf("redis: got %d elements in slowlog entry, want at least 4", len(fields))
+		}
+		entry := &cmd.result[i]
+		if entry.ID, err = toInt64(fields[0]); err != nil {
+			return err
+		}
+		sec, err := toInt64(fields[1])
+		if err != nil {
+			return err
+		}
+		entry.Time = time.Unix(sec, 0)
+		usec, err := toInt64(fields[2])
+		if err != nil {
+			return err
+		}
+		entry.Duration = time.Duration(usec) * time.Microsecond
+		if entry.Args, err = toStrings(fields[3]); err != nil {
+			return err
+		}
+		if len(fields) >= 6 {
+			if entry.ClientAddr, err = toString(fields[4]); err != nil {
+				return err
+			}
+			if entry.ClientName, err = toString(fields[5]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (cmd *SlowLogCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *SlowLogCmd) Result() ([]SlowLog, error) {
+	return cmd.result, cmd.err
+}
+
+type LatencyEvent struct {
+	Name   string
+	Time   time.Time
+	Latest time.Duration
+	Max    time.Duration
+}
+
+type LatencyLatestCmd struct {
+	*baseCmd
+	result []LatencyEvent
+}
+
+func (cmd *LatencyLatestCmd) ReadReply(val interface{}) error {
+	events, err := toSlice(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = make([]LatencyEvent, len(events))
+	for i, e := range events {
+		fields, err := toSlice(e)
+		if err != nil {
+			return err
+		}
+		if len(fields) < 4 {
+			return fmt.Errorf("redis: got %d elements in latency event, want 4", len(fields))
+		}
+		event := &cmd.result[i]
+		if event.Name, err = toString(fields[0]); err != nil {
+			return err
+		}
+		sec, err := toInt64(fields[1])
+		if err != nil {
+			return err
+		}
+		event.Time = time.Unix(sec, 0)
+		latest, err := toInt64(fields[2])
+		if err != nil {
+			return err
+		}
+		event.Latest = time.Duration(latest) * time.Millisecond
+		maxMs, err := toInt64(fields[3])
+		if err != nil {
+			return err
+		}
+		event.Max = time.Duration(maxMs) * time.Millisecond
+	}
+	return nil
+}
+
+func (cmd *LatencyLatestCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *LatencyLatestCmd) Result() ([]LatencyEvent, error) {
+	return cmd.result, cmd.err
+}
+
+type LatencySample struct {
+	Time    time.Time
+	Latency time.Duration
+}
+
+type LatencyHistoryCmd struct {
+	*baseCmd
+	result []LatencySample
+}
+
+func (cmd *LatencyHistoryCmd) ReadReply(val interface{}) error {
+	samples, err := toSlice(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = make([]LatencySample, len(samples))
+	for i, s := range samples {
+		fields, err := toSlice(s)
+		if err != nil {
+			return err
+		}
+		if len(fields) != 2 {
+			return fmt.Errorf("redis: got %d elements in latency sample, want 2", len(fields))
+		}
+		sec, err := toInt64(fields[0])
+		if err != nil {
+			return err
+		}
+		ms, err := toInt64(fields[1])
+		if err != nil {
+			return err
+		}
+		cmd.result[i] = LatencySample{
+			Time:    time.Unix(sec, 0),
+			Latency: time.Duration(ms) * time.Millisecond,
+		}
+	}
+	return nil
+}
+
+func (cmd *LatencyHistoryCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *LatencyHistoryCmd) Result() ([]LatencySample, error) {
+	return cmd.result, cmd.err
+}
+
+// CommandHistogram is the latency distribution of a command. Histogram maps
+// the upper bound of each bucket, in microseconds, to its number of calls.
+type CommandHistogram struct {
+	Calls     int64
+	Histogram map[int64]int64
+}
+
+type LatencyHistogramCmd struct {
+	*baseCmd
+	result map[string]CommandHistogram
+}
+
+func (cmd *LatencyHistogramCmd) ReadReply(val interface{}) error {
+	commands, err := toPairs(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = make(map[string]CommandHistogram, len(commands)/2)
+	for i := 0; i < len(commands); i += 2 {
+		name, err := toString(commands[i])
+		if err != nil {
+			return err
+		}
+		fields, err := toPairs(commands[i+1])
+		if err != nil {
+			return err
+		}
+		var h CommandHistogram
+		for j := 0; j < len(fields); j += 2 {
+			k, err := toString(fields[j])
+			if err != nil {
+				return err
+			}
+			switch k {
+			case "calls":
+				if h.Calls, err = toInt64(fields[j+1]); err != nil {
+					return err
+				}
+			case "histogram_usec":
+				buckets, err := toPairs(fields[j+1])
+				if err != nil {
+					return err
+				}
+				h.Histogram = make(map[int64]int64, len(buckets)/2)
+				for b := 0; b < len(buckets); b += 2 {
+					bound, err := toInt64(buckets[b])
+					if err != nil {
+						return err
+					}
+					if h.Histogram[bound], err = toInt64(buckets[b+1]); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		cmd.result[name] = h
+	}
+	return nil
+}
+
+func (cmd *LatencyHistogramCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *LatencyHistogramCmd) Result() (map[string]CommandHistogram, error) {
+	return cmd.result, cmd.err
+}
+
+// MemoryStats holds the common MEMORY STATS fields; Raw keeps every field
+// as replied, including those added by newer servers.
+type MemoryStats struct {
+	PeakAllocated      int64
+	TotalAllocated     int64
+	StartupAllocated   int64
+	ReplicationBacklog int64
+	ClientsReplicas    int64
+	ClientsNormal      int64
+	AOFBuffer          int64
+	LuaCaches          int64
+	FunctionsCaches    int64
+	OverheadTotal      int64
+	KeysCount          int64
+	KeysBytesPerKey    int64
+	DatasetBytes       int64
+	DatasetPercentage  float64
+	PeakPercentage     float64
+	Fragmentation      float64
+	FragmentationBytes int64
+	// keyed by database number
+	DB  map[int]MemoryStatsDB
+	Raw map[string]interface{}
+}
+
+type MemoryStatsDB struct {
+	OverheadHashtableMain    int64
+	OverheadHashtableExpires int64
+}
+
+type MemoryStatsCmd struct {
+	*baseCmd
+	result *MemoryStats
+}
+
+func (cmd *MemoryStatsCmd) ReadReply(val interface{}) error {
+	pairs, err := toPairs(val)
+	if err != nil {
+		return err
+	}
+	stats := &MemoryStats{
+		DB:  make(map[int]MemoryStatsDB),
+		Raw: make(map[string]interface{}, len(pairs)/2),
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		k, err := toString(pairs[i])
+		if err != nil {
+			return err
+		}
+		v := pairs[i+1]
+		stats.Raw[k] = v
+
+		switch k {
+		case "peak.allocated":
+			stats.PeakAllocated, err = toInt64(v)
+		case "total.allocated":
+			stats.TotalAllocated, err = toInt64(v)
+		case "startup.allocated":
+			stats.StartupAllocated, err = toInt64(v)
+		case "replication.backlog":
+			stats.ReplicationBacklog, err = toInt64(v)
+		case "clients.slaves":
+			stats.ClientsReplicas, err = toInt64(v)
+		case "clients.normal":
+			stats.ClientsNormal, err = toInt64(v)
+		case "aof.buffer":
+			stats.AOFBuffer, err = toInt64(v)
+		case "lua.caches":
+			stats.LuaCaches, err = toInt64(v)
+		case "functions.caches":
+			stats.FunctionsCaches, err = toInt64(v)
+		case "overhead.total":
+			stats.OverheadTotal, err = toInt64(v)
+		case "keys.count":
+			stats.KeysCount, err = toInt64(v)
+		case "keys.bytes-per-key":
+			stats.KeysBytesPerKey, err = toInt64(v)
+		case "dataset.bytes":
+			stats.DatasetBytes, err = toInt64(v)
+		case "dataset.percentage":
+			stats.DatasetPercentage, err = toFloat64(v)
+		case "peak.percentage":
+			stats.PeakPercentage, err = toFloat64(v)
+		case "fragmentation":
+			stats.Fragmentation, err = toFloat64(v)
+		case "fragmentation.bytes":
+			stats.FragmentationBytes, err = toInt64(v)
+		default:
+			if n, ok := memoryStatsDB(k); ok {
+				err = stats.parseDB(n, v)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("redis: invalid memory stats field %s: %w", k, err)
+		}
+	}
+	cmd.result = stats
+	return nil
+}
+
+// memoryStatsDB returns n for the db.<n> keys, not for other db.* keys
+// such as db.dict.rehashing.count of Redis 7.4.
+func memoryStatsDB(k string) (int, bool) {
+	suffix, ok := strings.CutPrefix(k, "db.")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(suffix)
+	return n, err == nil
+}
+
+func (stats *MemoryStats) parseDB(n int, v interface{}) error {
+	fields, err := toPairs(v)
+	if err != nil {
+		return err
+	}
+	var db MemoryStatsDB
+	for i := 0; i < len(fields); i += 2 {
+		name, err := toString(fields[i])
+		if err != nil {
+			return err
+		}
+		switch name {
+		case "overhead.hashtable.main":
+			db.OverheadHashtableMain, err = toInt64(fields[i+1])
+		case "overhead.hashtable.expires":
+			db.OverheadHashtableExpires, err = toInt64(fields[i+1])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	stats.DB[n] = db
+	return nil
+}
+
+func (cmd *MemoryStatsCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *MemoryStatsCmd) Result() (*MemoryStats, error) {
+	return cmd.result, cmd.err
+}
@@ -0,0 +1,118 @@
+package go_redis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLatencyHistogramCmd(t *testing.T) {
+	want := map[string]CommandHistogram{
+		"set": {Calls: 3, Histogram: map[int64]int64{1: 1, 4: 2}},
+	}
+
+	replies := map[string]interface{}{
+		"resp2": []interface{}{
+			"set", []interface{}{
+				"calls", int64(3),
+				"histogram_usec", []interface{}{int64(1), int64(1), int64(4), int64(2)},
+			},
+		},
+		"resp3": map[interface{}]interface{}{
+			"set": map[interface{}]interface{}{
+				"calls":          int64(3),
+				"histogram_usec": map[interface{}]interface{}{int64(1): int64(1), int64(4): int64(2)},
+			},
+		},
+	}
+	for name, reply := range replies {
+		cmd := &LatencyHistogramCmd{baseCmd: &baseCmd{}}
+		if err := cmd.ReadReply(reply); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got, _ := cmd.Result(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestSlowLogCmd(t *testing.T) {
+	cmd := &SlowLogCmd{baseCmd: &baseCmd{}}
+	err := cmd.ReadReply([]interface{}{
+		[]interface{}{int64(14), int64(1700000000), int64(25000), []interface{}{"KEYS", "*"}, "127.0.0.1:52555", "billing"},
+		// before Redis 4.0
+		[]interface{}{int64(13), int64(1699999999), int64(12), []interface{}{"GET", "k"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SlowLog{
+		{ID: 14, Time: time.Unix(1700000000, 0), Duration: 25 * time.Millisecond, Args: []string{"KEYS", "*"},
+			ClientAddr: "127.0.0.1:52555", ClientName: "billing"},
+		{ID: 13, Time: time.Unix(1699999999, 0), Duration: 12 * time.Microsecond, Args: []string{"GET", "k"}},
+	}
+	if got, _ := cmd.Result(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if err := cmd.ReadReply([]interface{}{[]interface{}{int64(1), int64(2)}}); err == nil {
+		t.Fatal("want error for a short entry")
+	}
+}
+
+func TestLatencyLatestCmd(t *testing.T) {
+	cmd := &LatencyLatestCmd{baseCmd: &baseCmd{}}
+	err := cmd.ReadReply([]interface{}{
+		[]interface{}{"command", int64(1700000000), int64(250), int64(1000)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LatencyEvent{
+		{Name: "command", Time: time.Unix(1700000000, 0), Latest: 250 * time.Millisecond, Max: time.Second},
+	}
+	if got, _ := cmd.Result(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestMemoryStatsCmd(t *testing.T) {
+	replies := map[string]interface{}{
+		"resp2": []interface{}{
+			"peak.allocated", int64(2000),
+			"total.allocated", int64(1000),
+			"keys.count", int64(10),
+			"dataset.percentage", "42.5",
+			"db.0", []interface{}{"overhead.hashtable.main", int64(72), "overhead.hashtable.expires", int64(8)},
+			// Redis 7.4
+			"db.dict.rehashing.count", int64(0),
+			"fragmentation", "1.25",
+		},
+		"resp3": map[interface{}]interface{}{
+			"peak.allocated":          int64(2000),
+			"total.allocated":         int64(1000),
+			"keys.count":              int64(10),
+			"dataset.percentage":      42.5,
+			"db.0":                    map[interface{}]interface{}{"overhead.hashtable.main": int64(72), "overhead.hashtable.expires": int64(8)},
+			"db.dict.rehashing.count": int64(0),
+			"fragmentation":           1.25,
+		},
+	}
+	for name, reply := range replies {
+		cmd := &MemoryStatsCmd{baseCmd: &baseCmd{}}
+		if err := cmd.ReadReply(reply); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		stats, _ := cmd.Result()
+		if stats.PeakAllocated != 2000 || stats.TotalAllocated != 1000 || stats.KeysCount != 10 ||
+			stats.DatasetPercentage != 42.5 || stats.Fragmentation != 1.25 {
+			t.Fatalf("%s: got %+v", name, stats)
+		}
+		if want := map[int]MemoryStatsDB{0: {OverheadHashtableMain: 72, OverheadHashtableExpires: 8}}; !reflect.DeepEqual(stats.DB, want) {
+			t.Fatalf("%s: got %+v, want %+v", name, stats.DB, want)
+		}
+		if _, ok := stats.Raw["db.dict.rehashing.count"]; !ok {
+			t.Fatalf("%s: db.dict.rehashing.count missing from Raw", name)
+		}
+	}
+}
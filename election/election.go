@@ -0,0 +1,263 @@
+// Package election elects a single leader among the replicas of a service,
+// holding a Redis key with the leader ID under an expiring lease:
+//
+//	e := election.New(redis)
+//	leadership, err := e.Campaign(ctx, "cron", hostname, 10*time.Second)
+//	if err != nil {
+//	}
+//	defer leadership.Resign(context.Background())
+//	runJobs(leadership.Done())
+//
+// Every change of leader is published on a pub/sub channel, which
+// Observe follows.
+package election
+
+import (
+	"context"
+	"errors"
+	go_redis "github.com/mingolm/go-redis"
+	"github.com/mingolm/go-redis/proto"
+	"sync"
+	"time"
+)
+
+// campaignScript takes the lead if vacant, or keeps it for the same ID.
+// KEYS[1] leader key, ARGV: id, ttl ms, channel.
+var campaignScript = go_redis.NewScript(`
+local leader = redis.call("GET", KEYS[1])
+if not leader then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	redis.call("PUBLISH", ARGV[3], ARGV[1])
+	return 1
+end
+if leader == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+var renewScript = go_redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// resignScript steps down and announces the vacancy with an empty ID.
+var resignScript = go_redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("DEL", KEYS[1])
+	redis.call("PUBLISH", ARGV[2], "")
+	return 1
+end
+return 0
+`)
+
+// observePoll is how often Observe reads the leader, to notice a leader
+// whose lease expired without a successor announcing itself.
+const observePoll = time.Second
+
+type Client struct {
+	rdb *go_redis.Redis
+}
+
+func New(rdb *go_redis.Redis) *Client {
+	return &Client{rdb: rdb}
+}
+
+func leaderKey(name string) string {
+	return "election:" + name
+}
+
+func channel(name string) string {
+	return "election:" + name + ":leader"
+}
+
+// Campaign blocks until id is the leader of the election name, or ctx is
+// done. Candidates try to take the lead every third of ttl, so a crashed
+// leader is replaced within about 1.3 ttl. The lease is then renewed in
+// the background until Resign.
+func (c *Client) Campaign(ctx context.Context, name, id string, ttl time.Duration) (*Leadership, error) {
+	interval := ttl / 3
+	var start time.Time
+	for {
+		start = time.Now()
+		ok, err := campaignScript.Run(ctx, c.rdb, []string{leaderKey(name)}, id, ttl.Milliseconds(), channel(name)).Bool()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			break
+		}
+
+		t := time.NewTimer(interval)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	l := &Leadership{
+		c:    c,
+		name: name,
+		id:   id,
+		done: make(chan struct{}),
+	}
+	go l.renew(ttl, start)
+	return l, nil
+}
+
+// Leader returns the ID of the current leader, empty if there is none.
+func (c *Client) Leader(ctx context.Context, name string) (string, error) {
+	id, err := c.rdb.Get(ctx, leaderKey(name)).Result()
+	if errors.Is(err, proto.Nil) {
+		return "", nil
+	}
+	return id, err
+}
+
+// Observe returns a channel receiving the current leader ID, then every
+// change of leader, empty while there is none. It is closed when ctx is
+// done.
+func (c *Client) Observe(ctx context.Context, name string) (<-chan string, error) {
+	ps, err := c.rdb.Subscribe(ctx, channel(name))
+	if err != nil {
+		return nil, err
+	}
+	// subscribed before reading, not to miss a change in between
+	leader, err := c.Leader(ctx, name)
+	if err != nil {
+		_ = ps.Close()
+		return nil, err
+	}
+
+	ch := make(chan string, 1)
+	ch <- leader
+	go func() {
+		defer close(ch)
+		defer ps.Close()
+
+		t := time.NewTicker(observePoll)
+		defer t.Stop()
+
+		for {
+			current := leader
+			select {
+			case msg, ok := <-ps.Channel():
+				if !ok {
+					return
+				}
+				current = msg.Payload
+			case <-t.C:
+				id, err := c.Leader(ctx, name)
+				if err != nil {
+					continue
+				}
+				current = id
+			case <-ctx.Done():
+				return
+			}
+			if current == leader {
+				continue
+			}
+			leader = current
+
+			select {
+			case ch <- leader:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// Leadership is held by the elected candidate.
+type Leadership struct {
+	c    *Client
+	name string
+	id   string
+
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+func (l *Leadership) ID() string {
+	return l.id
+}
+
+// Done is closed once the leadership is resigned or lost, when another
+// candidate took the lead or the lease could not be renewed before
+// expiring. Work reserved to the leader must stop then.
+func (l *Leadership) Done() <-chan struct{} {
+	return l.done
+}
+
+func (l *Leadership) close() {
+	l.doneOnce.Do(func() {
+		close(l.done)
+	})
+}
+
+// Resign steps down, letting another candidate take the lead.
+func (l *Leadership) Resign(ctx context.Context) error {
+	l.close()
+	return resignScript.Run(ctx, l.c.rdb, []string{leaderKey(l.name)}, l.id, channel(l.name)).Err()
+}
+
+type renewal struct {
+	start time.Time
+	ok    bool
+	err   error
+}
+
+// renew extends the lease every third of ttl, from the lease taken at
+// start. Failed renewals are retried, but the leadership is given up an
+// interval before the lease may expire, even while a renewal hangs, so
+// that it is never held once a successor may take it over.
+func (l *Leadership) renew(ttl time.Duration, start time.Time) {
+	interval := ttl / 3
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	// the lease runs from as early as the request was sent
+	lost := time.NewTimer(ttl - interval - time.Since(start))
+	defer lost.Stop()
+
+	var (
+		results = make(chan renewal, 1)
+		pending bool
+	)
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-lost.C:
+			l.close()
+			return
+		case <-t.C:
+			if pending {
+				continue
+			}
+			pending = true
+			go func(start time.Time) {
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				defer cancel()
+				ok, err := renewScript.Run(ctx, l.c.rdb, []string{leaderKey(l.name)}, l.id, ttl.Milliseconds()).Bool()
+				results <- renewal{start: start, ok: ok, err: err}
+			}(time.Now())
+		case r := <-results:
+			pending = false
+			switch {
+			case r.err == nil && r.ok:
+				lost.Reset(ttl - interval - time.Since(r.start))
+			case r.err == nil:
+				// another candidate took the lead
+				l.close()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package election
+
+import (
+	"context"
+	"fmt"
+	"github.com/mingolm/go-redis/internal/redistest"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeElection runs the scripts of a single election in memory.
+type fakeElection struct {
+	mu        sync.Mutex
+	leader    string
+	expires   time.Time
+	down      bool // renewals fail
+	subs      []net.Conn
+	published []string
+	subCh     chan struct{}
+}
+
+func newFakeElection() *fakeElection {
+	return &fakeElection{subCh: make(chan struct{}, 10)}
+}
+
+func (f *fakeElection) current() string {
+	if time.Now().After(f.expires) {
+		f.leader = ""
+	}
+	return f.leader
+}
+
+func (f *fakeElection) publish(payload string) {
+	f.published = append(f.published, payload)
+	for _, cn := range f.subs {
+		fmt.Fprintf(cn, "*3\r\n$7\r\nmessage\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n",
+			len(channel("cron")), channel("cron"), len(payload), payload)
+	}
+}
+
+func (f *fakeElection) handle(cn net.Conn, args []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch args[0] {
+	case "GET":
+		if leader := f.current(); leader != "" {
+			redistest.Bulk(cn, leader)
+			return
+		}
+		redistest.Nil(cn)
+	case "SUBSCRIBE":
+		f.subs = append(f.subs, cn)
+		redistest.Strings(cn, "subscribe", args[1])
+		f.subCh <- struct{}{}
+	case "EVALSHA":
+		// EVALSHA sha 1 key id ...
+		id := args[4]
+		switch args[1] {
+		case campaignScript.Hash():
+			ttl, _ := strconv.Atoi(args[5])
+			switch f.current() {
+			case "":
+				f.leader, f.expires = id, time.Now().Add(time.Duration(ttl)*time.Millisecond)
+				f.publish(id)
+			case id:
+				f.expires = time.Now().Add(time.Duration(ttl) * time.Millisecond)
+			default:
+				redistest.Int(cn, 0)
+				return
+			}
+			redistest.Int(cn, 1)
+		case renewScript.Hash():
+			if f.down {
+				redistest.Error(cn, "ERR unreachable")
+				return
+			}
+			ttl, _ := strconv.Atoi(args[5])
+			if f.current() != id {
+				redistest.Int(cn, 0)
+				return
+			}
+			f.expires = time.Now().Add(time.Duration(ttl) * time.Millisecond)
+			redistest.Int(cn, 1)
+		case resignScript.Hash():
+			if f.current() != id {
+				redistest.Int(cn, 0)
+				return
+			}
+			f.leader = ""
+			f.publish("")
+			redistest.Int(cn, 1)
+		}
+	default:
+		redistest.Error(cn, "ERR unknown command")
+	}
+}
+
+func TestCampaign(t *testing.T) {
+	f := newFakeElection()
+	c := New(redistest.NewClient(f.handle))
+	ctx := context.Background()
+
+	l, err := c.Campaign(ctx, "cron", "a", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := c.Leader(ctx, "cron"); err != nil || id != "a" {
+		t.Fatalf("got leader %q, %v", id, err)
+	}
+
+	// another candidate waits for the lead
+	ctx2, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.Campaign(ctx2, "cron", "b", 90*time.Millisecond); err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want DeadlineExceeded", err)
+	}
+
+	if err := l.Resign(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-l.Done():
+	default:
+		t.Fatal("done not closed on resign")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if want := []string{"a", ""}; fmt.Sprint(f.published) != fmt.Sprint(want) {
+		t.Fatalf("published %q, want %q", f.published, want)
+	}
+}
+
+func TestRenewLost(t *testing.T) {
+	f := newFakeElection()
+	c := New(redistest.NewClient(f.handle))
+	ctx := context.Background()
+	ttl := 150 * time.Millisecond
+
+	l, err := c.Campaign(ctx, "cron", "a", ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.mu.Lock()
+	f.leader = "b"
+	f.mu.Unlock()
+	select {
+	case <-l.Done():
+	case <-time.After(time.Second):
+		t.Fatal("leadership taken over but not lost")
+	}
+
+	// cut off from Redis, the lead is given up before the lease expires
+	f.mu.Lock()
+	f.leader = ""
+	f.mu.Unlock()
+	start := time.Now()
+	l, err = c.Campaign(ctx, "cron", "a", ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.mu.Lock()
+	f.down = true
+	f.mu.Unlock()
+	select {
+	case <-l.Done():
+		if time.Since(start) >= ttl {
+			t.Fatalf("lost after %v, past the lease of %v", time.Since(start), ttl)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("leadership not lost")
+	}
+}
+
+func TestObserve(t *testing.T) {
+	f := newFakeElection()
+	c := New(redistest.NewClient(f.handle))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := c.Observe(ctx, "cron")
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-f.subCh
+	recv := func() string {
+		select {
+		case id := <-ch:
+			return id
+		case <-time.After(time.Second):
+			t.Fatal("no leader received")
+			return ""
+		}
+	}
+	if id := recv(); id != "" {
+		t.Fatalf("got %q, want no leader", id)
+	}
+
+	l, err := c.Campaign(ctx, "cron", "a", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := recv(); id != "a" {
+		t.Fatalf("got %q, want a", id)
+	}
+	// a repeated announcement is not a change
+	f.mu.Lock()
+	f.publish("a")
+	f.mu.Unlock()
+	if err := l.Resign(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if id := recv(); id != "" {
+		t.Fatalf("got %q after resign, want no leader", id)
+	}
+
+	cancel()
+	for range ch {
+	}
+}
@@ -0,0 +1,443 @@
+package go_redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mingolm/go-redis/proto"
+	"strings"
+	"sync"
+	"time"
+)
+
+type FunctionCmdable interface {
+	FunctionLoad(ctx context.Context, code string, replace bool) *StringCmd
+	FunctionDelete(ctx context.Context, libName string) *StatusCmd
+	FunctionFlush(ctx context.Context, mode string) *StatusCmd
+	FunctionList(ctx context.Context, q *FunctionListQuery) *FunctionListCmd
+	FunctionDump(ctx context.Context) *StringCmd
+	FunctionRestore(ctx context.Context, payload string, policy string) *StatusCmd
+	FunctionStats(ctx context.Context) *FunctionStatsCmd
+	FCall(ctx context.Context, function string, keys []string, args ...interface{}) *Cmd
+	FCallRO(ctx context.Context, function string, keys []string, args ...interface{}) *Cmd
+}
+
+// FUNCTION RESTORE policies. Empty is APPEND.
+const (
+	FunctionRestoreAppend  = "APPEND"
+	FunctionRestoreReplace = "REPLACE"
+	FunctionRestoreFlush   = "FLUSH"
+)
+
+type FunctionListQuery struct {
+	LibraryNamePattern string
+	WithCode           bool
+}
+
+// FunctionLoad loads a library and returns its name.
+func (c cmdable) FunctionLoad(ctx context.Context, code string, replace bool) *StringCmd {
+	args := []interface{}{"FUNCTION", "LOAD"}
+	if replace {
+		args = append(args, "replace")
+	}
+	args = append(args, code)
+	cmd := newStringCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) FunctionDelete(ctx context.Context, libName string) *StatusCmd {
+	cmd := newStatusCmd(ctx, "FUNCTION", "DELETE", libName)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) FunctionFlush(ctx context.Context, mode string) *StatusCmd {
+	args := []interface{}{"FUNCTION", "FLUSH"}
+	if mode != "" {
+		args = append(args, strings.ToLower(mode))
+	}
+	cmd := newStatusCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) FunctionList(ctx context.Context, q *FunctionListQuery) *FunctionListCmd {
+	args := []interface{}{"FUNCTION", "LIST"}
+	if q != nil {
+		if q.LibraryNamePattern != "" {
+			args = append(args, "libraryname", q.LibraryNamePattern)
+		}
+		if q.WithCode {
+			args = append(args, "withcode")
+		}
+	}
+	cmd := &FunctionListCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// FunctionDump returns a serialized payload of all libraries, to be used
+// with FunctionRestore.
+func (c cmdable) FunctionDump(ctx context.Context) *StringCmd {
+	cmd := newStringCmd(ctx, "FUNCTION", "DUMP")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) FunctionRestore(ctx context.Context, payload string, policy string) *StatusCmd {
+	args := []interface{}{"FUNCTION", "RESTORE", payload}
+	if policy != "" {
+		args = append(args, strings.ToLower(policy))
+	}
+	cmd := newStatusCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) FunctionStats(ctx context.Context) *FunctionStatsCmd {
+	cmd := &FunctionStatsCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: []interface{}{"FUNCTION", "STATS"},
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) FCall(ctx context.Context, function string, keys []string, args ...interface{}) *Cmd {
+	cmd := newCmd(ctx, fcallArgs("FCALL", function, keys, args)...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) FCallRO(ctx context.Context, function string, keys []string, args ...interface{}) *Cmd {
+	cmd := newCmd(ctx, fcallArgs("FCALL_RO", function, keys, args)...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func fcallArgs(name, function string, keys []string, args []interface{}) []interface{} {
+	cmdArgs := make([]interface{}, 3, 3+len(keys)+len(args))
+	cmdArgs[0] = name
+	cmdArgs[1] = function
+	cmdArgs[2] = len(keys)
+	for _, key := range keys {
+		cmdArgs = append(cmdArgs, key)
+	}
+	return append(cmdArgs, args...)
+}
+
+type FunctionInfo struct {
+	Name        string
+	Description string
+	Flags       []string
+}
+
+type LibraryInfo struct {
+	Name      string
+	Engine    string
+	Functions []FunctionInfo
+	// only set with FunctionListQuery.WithCode
+	Code string
+}
+
+type FunctionListCmd struct {
+	*baseCmd
+	result []LibraryInfo
+}
+
+func (cmd *FunctionListCmd) ReadReply(val interface{}) error {
+	libs, err := toSlice(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = make([]LibraryInfo, len(libs))
+	for i, lib := range libs {
+		if cmd.result[i], err = parseLibraryInfo(lib); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseLibraryInfo(val interface{}) (lib LibraryInfo, err error) {
+	pairs, err := toPairs(val)
+	if err != nil {
+		return lib, err
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		k, err := toString(pairs[i])
+		if err != nil {
+			return lib, err
+		}
+		v := pairs[i+1]
+		switch k {
+		case "library_name":
+			lib.Name, err = toString(v)
+		case "engine":
+			lib.Engine, err = toString(v)
+		case "library_code":
+			lib.Code, err = toString(v)
+		case "functions":
+			var functions []interface{}
+			if functions, err = toSlice(v); err != nil {
+				break
+			}
+			lib.Functions = make([]FunctionInfo, len(functions))
+			for j, f := range functions {
+				if lib.Functions[j], err = parseFunctionInfo(f); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			return lib, err
+		}
+	}
+	return lib, nil
+}
+
+func parseFunctionInfo(val interface{}) (f FunctionInfo, err error) {
+	pairs, err := toPairs(val)
+	if err != nil {
+		return f, err
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		k, err := toString(pairs[i])
+		if err != nil {
+			return f, err
+		}
+		v := pairs[i+1]
+		switch k {
+		case "name":
+			f.Name, err = toString(v)
+		case "description":
+			if v != nil {
+				f.Description, err = toString(v)
+			}
+		case "flags":
+			f.Flags, err = toStrings(v)
+		}
+		if err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
+func (cmd *FunctionListCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *FunctionListCmd) Result() ([]LibraryInfo, error) {
+	return cmd.result, cmd.err
+}
+
+type RunningScript struct {
+	Name     string
+	Command  []string
+	Duration time.Duration
+}
+
+type EngineStats struct {
+	LibrariesCount int64
+	FunctionsCount int64
+}
+
+type FunctionStats struct {
+	// nil when no function is running
+	RunningScript *RunningScript
+	Engines       map[string]EngineStats
+}
+
+type FunctionStatsCmd struct {
+	*baseCmd
+	result *FunctionStats
+}
+
+func (cmd *FunctionStatsCmd) ReadReply(val interface{}) error {
+	pairs, err := toPairs(val)
+	if err != nil {
+		return err
+	}
+	stats := &FunctionStats{
+		Engines: make(map[string]EngineStats),
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		k, err := toString(pairs[i])
+		if err != nil {
+			return err
+		}
+		v := pairs[i+1]
+		switch k {
+		case "running_script":
+			if v != nil {
+				stats.RunningScript, err = parseRunningScript(v)
+			}
+		case "engines":
+			err = stats.parseEngines(v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	cmd.result = stats
+	return nil
+}
+
+func parseRunningScript(val interface{}) (*RunningScript, error) {
+	pairs, err := toPairs(val)
+	if err != nil {
+		return nil, err
+	}
+	script := &RunningScript{}
+	for i := 0; i < len(pairs); i += 2 {
+		k, err := toString(pairs[i])
+		if err != nil {
+			return nil, err
+		}
+		v := pairs[i+1]
+		switch k {
+		case "name":
+			script.Name, err = toString(v)
+		case "command":
+			script.Command, err = toStrings(v)
+		case "duration_ms":
+			var ms int64
+			ms, err = toInt64(v)
+			script.Duration = time.Duration(ms) * time.Millisecond
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return script, nil
+}
+
+func (stats *FunctionStats) parseEngines(val interface{}) error {
+	engines, err := toPairs(val)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(engines); i += 2 {
+		name, err := toString(engines[i])
+		if err != nil {
+			return err
+		}
+		fields, err := toPairs(engines[i+1])
+		if err != nil {
+			return err
+		}
+		var es EngineStats
+		for j := 0; j < len(fields); j += 2 {
+			k, err := toString(fields[j])
+			if err != nil {
+				return err
+			}
+			switch k {
+			case "libraries_count":
+				es.LibrariesCount, err = toInt64(fields[j+1])
+			case "functions_count":
+				es.FunctionsCount, err = toInt64(fields[j+1])
+			}
+			if err != nil {
+				return err
+			}
+		}
+		stats.Engines[name] = es
+	}
+	return nil
+}
+
+func (cmd *FunctionStatsCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *FunctionStatsCmd) Result() (*FunctionStats, error) {
+	return cmd.result, cmd.err
+}
+
+// Library keeps a function library loaded on the server:
+//
+//	lib := NewLibrary("#!lua name=counters\n...")
+//	if err := lib.Load(ctx, redis); err != nil {
+//	}
+//	n, err := lib.FCall(ctx, redis, "incr_capped", []string{"k"}, 10).Int64()
+//
+// Calls that fail because the function is missing (e.g. after FUNCTION
+// FLUSH or a failover to a replica without it) reload the library and are
+// retried once. If the reload fails, its error is returned instead.
+type Library struct {
+	name string
+	code string
+	mu   sync.Mutex
+}
+
+// NewLibrary takes the library source, whose first line must be a shebang
+// such as "#!lua name=mylib".
+func NewLibrary(code string) *Library {
+	return &Library{
+		name: libraryName(code),
+		code: code,
+	}
+}
+
+func libraryName(code string) string {
+	shebang, _, _ := strings.Cut(code, "\n")
+	for _, field := range strings.Fields(shebang) {
+		if name, ok := strings.CutPrefix(field, "name="); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+func (l *Library) Name() string {
+	return l.name
+}
+
+// Load loads or replaces the library on the server.
+func (l *Library) Load(ctx context.Context, c Cmdable) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return c.FunctionLoad(ctx, l.code, true).Err()
+}
+
+func (l *Library) FCall(ctx context.Context, c Cmdable, function string, keys []string, args ...interface{}) *Cmd {
+	cmd := c.FCall(ctx, function, keys, args...)
+	if !isFunctionNotFound(cmd.Err()) {
+		return cmd
+	}
+	if err := l.Load(ctx, c); err != nil {
+		cmd.err = err
+		return cmd
+	}
+	return c.FCall(ctx, function, keys, args...)
+}
+
+func (l *Library) FCallRO(ctx context.Context, c Cmdable, function string, keys []string, args ...interface{}) *Cmd {
+	cmd := c.FCallRO(ctx, function, keys, args...)
+	if !isFunctionNotFound(cmd.Err()) {
+		return cmd
+	}
+	if err := l.Load(ctx, c); err != nil {
+		cmd.err = err
+		return cmd
+	}
+	return c.FCallRO(ctx, function, keys, args...)
+}
+
+func isFunctionNotFound(err error) bool {
+	var rerr proto.RedisError
+	if !errors.As(err, &rerr) {
+		return false
+	}
+	return strings.HasPrefix(string(rerr), "ERR Function not found")
+}
@@ -0,0 +1,60 @@
+package go_redis
+
+import (
+	"context"
+	"errors"
+	"github.com/mingolm/go-redis/proto"
+	"reflect"
+	"testing"
+)
+
+func TestLibraryFCall(t *testing.T) {
+	lib := NewLibrary("#!lua name=counters\nredis.register_function('incr', function() return 1 end)")
+	if lib.Name() != "counters" {
+		t.Fatalf("got name %q", lib.Name())
+	}
+
+	var (
+		names   []string
+		loaded  bool
+		loadErr error
+	)
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		name := cmd.Args()[0].(string)
+		names = append(names, name)
+		switch name {
+		case "FUNCTION":
+			if loadErr != nil {
+				return loadErr
+			}
+			loaded = true
+			return cmd.ReadReply("counters")
+		case "FCALL", "FCALL_RO":
+			if !loaded {
+				return proto.RedisError("ERR Function not found")
+			}
+			return cmd.ReadReply(int64(1))
+		}
+		return errors.New("unexpected command")
+	})
+	ctx := context.Background()
+
+	n, err := lib.FCall(ctx, c, "incr", []string{"k"}).Int64()
+	if err != nil || n != 1 {
+		t.Fatalf("got %d, %v", n, err)
+	}
+	if want := []string{"FCALL", "FUNCTION", "FCALL"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("got %v, want %v", names, want)
+	}
+
+	// the reload error is returned, not the missing function
+	names, loaded = nil, false
+	loadErr = proto.RedisError("READONLY You can't write against a read only replica.")
+	err = lib.FCallRO(ctx, c, "incr", []string{"k"}).Err()
+	if !errors.Is(err, loadErr) {
+		t.Fatalf("got %v, want %v", err, loadErr)
+	}
+	if want := []string{"FCALL_RO", "FUNCTION"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("got %v, want %v", names, want)
+	}
+}
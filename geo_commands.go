@@ -0,0 +1,351 @@
+package go_redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type GeoCmdable interface {
+	GeoAdd(ctx context.Context, key string, opt *GeoAddOptions, locations ...*GeoLocation) *IntCmd
+	GeoPos(ctx context.Context, key string, members ...string) *GeoPosCmd
+	GeoDist(ctx context.Context, key string, member1, member2, unit string) *FloatCmd
+	GeoHash(ctx context.Context, key string, members ...string) *StringSliceCmd
+	GeoSearch(ctx context.Context, key string, q *GeoSearchQuery) *GeoLocationCmd
+	GeoSearchStore(ctx context.Context, key, store string, q *GeoSearchStoreQuery) *IntCmd
+}
+
+// GeoLocation is a member of a geo set. Dist, GeoHash and the coordinates of
+// a GEOSEARCH result are only filled when requested with WITHDIST, WITHHASH
+// and WITHCOORD.
+type GeoLocation struct {
+	Name      string
+	Longitude float64
+	Latitude  float64
+	Dist      float64
+	GeoHash   int64
+}
+
+type GeoPos struct {
+	Longitude float64
+	Latitude  float64
+}
+
+type GeoAddOptions struct {
+	NX bool // only add new elements
+	XX bool // only update existing elements
+	CH bool // count changed elements instead of added
+}
+
+// GeoSearchQuery describes a GEOSEARCH.
+// The center is Member (FROMMEMBER) or, if Member is empty,
+// Longitude/Latitude (FROMLONLAT). The shape is Radius (BYRADIUS) or,
+// if Radius is zero, BoxWidth/BoxHeight (BYBOX).
+type GeoSearchQuery struct {
+	Member    string
+	Longitude float64
+	Latitude  float64
+
+	Radius    float64
+	BoxWidth  float64
+	BoxHeight float64
+	// m, km, ft or mi. Default is km.
+	Unit string
+
+	// ASC or DESC. Default is unsorted.
+	Sort     string
+	Count    int
+	CountAny bool
+
+	WithCoord bool
+	WithDist  bool
+	WithHash  bool
+}
+
+type GeoSearchStoreQuery struct {
+	GeoSearchQuery
+	// store the distance as score instead of the geohash
+	StoreDist bool
+}
+
+func (c cmdable) GeoAdd(ctx context.Context, key string, opt *GeoAddOptions, locations ...*GeoLocation) *IntCmd {
+	args := make([]interface{}, 2, 5+3*len(locations))
+	args[0] = "GEOADD"
+	args[1] = key
+	if opt != nil {
+		if opt.NX {
+			args = append(args, "nx")
+		} else if opt.XX {
+			args = append(args, "xx")
+		}
+		if opt.CH {
+			args = append(args, "ch")
+		}
+	}
+	for _, loc := range locations {
+		if loc == nil {
+			cmd := newIntCmd(ctx, args...)
+			cmd.err = errors.New("redis: nil geo location")
+			return cmd
+		}
+		args = append(args, loc.Longitude, loc.Latitude, loc.Name)
+	}
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) GeoPos(ctx context.Context, key string, members ...string) *GeoPosCmd {
+	args := make([]interface{}, 2, 2+len(members))
+	args[0] = "GEOPOS"
+	args[1] = key
+	for _, member := range members {
+		args = append(args, member)
+	}
+	cmd := &GeoPosCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) GeoDist(ctx context.Context, key string, member1, member2, unit string) *FloatCmd {
+	if unit == "" {
+		unit = "km"
+	}
+	cmd := newFloatCmd(ctx, "GEODIST", key, member1, member2, unit)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) GeoHash(ctx context.Context, key string, members ...string) *StringSliceCmd {
+	args := make([]interface{}, 2, 2+len(members))
+	args[0] = "GEOHASH"
+	args[1] = key
+	for _, member := range members {
+		args = append(args, member)
+	}
+	cmd := newStringSliceCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) GeoSearch(ctx context.Context, key string, q *GeoSearchQuery) *GeoLocationCmd {
+	args := []interface{}{"GEOSEARCH", key}
+	if q == nil {
+		cmd := &GeoLocationCmd{
+			baseCmd: &baseCmd{
+				ctx:  ctx,
+				args: args,
+			},
+		}
+		cmd.err = errNilGeoQuery
+		return cmd
+	}
+	args = q.appendArgs(args)
+	if q.WithCoord {
+		args = append(args, "withcoord")
+	}
+	if q.WithDist {
+		args = append(args, "withdist")
+	}
+	if q.WithHash {
+		args = append(args, "withhash")
+	}
+	cmd := &GeoLocationCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+		q: q,
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) GeoSearchStore(ctx context.Context, key, store string, q *GeoSearchStoreQuery) *IntCmd {
+	args := []interface{}{"GEOSEARCHSTORE", store, key}
+	if q == nil {
+		cmd := newIntCmd(ctx, args...)
+		cmd.err = errNilGeoQuery
+		return cmd
+	}
+	args = q.appendArgs(args)
+	if q.StoreDist {
+		args = append(args, "storedist")
+	}
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+var errNilGeoQuery = errors.New("redis: nil geo search query")
+
+func (q *GeoSearchQuery) appendArgs(args []interface{}) []interface{} {
+	if q.Member != "" {
+		args = append(args, "frommember", q.Member)
+	} else {
+		args = append(args, "fromlonlat", q.Longitude, q.Latitude)
+	}
+
+	unit := q.Unit
+	if unit == "" {
+		unit = "km"
+	}
+	if q.Radius > 0 {
+		args = append(args, "byradius", q.Radius, unit)
+	} else {
+		args = append(args, "bybox", q.BoxWidth, q.BoxHeight, unit)
+	}
+
+	if q.Sort != "" {
+		args = append(args, strings.ToLower(q.Sort))
+	}
+	if q.Count > 0 {
+		args = append(args, "count", q.Count)
+		if q.CountAny {
+			args = append(args, "any")
+		}
+	}
+	return args
+}
+
+type GeoPosCmd struct {
+	*baseCmd
+	result []*GeoPos
+}
+
+func (cmd *GeoPosCmd) ReadReply(val interface{}) error {
+	vals, err := toSlice(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = make([]*GeoPos, len(vals))
+	for i, v := range vals {
+		// missing member
+		if v == nil {
+			continue
+		}
+		pos, err := toSlice(v)
+		if err != nil {
+			return err
+		}
+		if cmd.result[i], err = parseGeoPos(pos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cmd *GeoPosCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *GeoPosCmd) Result() ([]*GeoPos, error) {
+	return cmd.result, cmd.err
+}
+
+type GeoLocationCmd struct {
+	*baseCmd
+	q      *GeoSearchQuery
+	result []GeoLocation
+}
+
+func (cmd *GeoLocationCmd) ReadReply(val interface{}) error {
+	vals, err := toSlice(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = make([]GeoLocation, len(vals))
+	for i, v := range vals {
+		if cmd.result[i], err = cmd.parseLocation(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseLocation decodes either a plain member name or, with any WITH*
+// option, [name, dist?, hash?, [lon, lat]?] in that order.
+func (cmd *GeoLocationCmd) parseLocation(val interface{}) (loc GeoLocation, err error) {
+	if !cmd.q.WithCoord && !cmd.q.WithDist && !cmd.q.WithHash {
+		loc.Name, err = toString(val)
+		return loc, err
+	}
+
+	fields, err := toSlice(val)
+	if err != nil {
+		return loc, err
+	}
+	if len(fields) == 0 {
+		return loc, fmt.Errorf("redis: empty geo location reply")
+	}
+	if loc.Name, err = toString(fields[0]); err != nil {
+		return loc, err
+	}
+	fields = fields[1:]
+
+	if cmd.q.WithDist {
+		if len(fields) == 0 {
+			return loc, fmt.Errorf("redis: missing geo location dist")
+		}
+		if loc.Dist, err = toFloat64(fields[0]); err != nil {
+			return loc, err
+		}
+		fields = fields[1:]
+	}
+	if cmd.q.WithHash {
+		if len(fields) == 0 {
+			return loc, fmt.Errorf("redis: missing geo location hash")
+		}
+		if loc.GeoHash, err = toInt64(fields[0]); err != nil {
+			return loc, err
+		}
+		fields = fields[1:]
+	}
+	if cmd.q.WithCoord {
+		if len(fields) == 0 {
+			return loc, fmt.Errorf("redis: missing geo location coord")
+		}
+		coord, err := toSlice(fields[0])
+		if err != nil {
+			return loc, err
+		}
+		pos, err := parseGeoPos(coord)
+		if err != nil {
+			return loc, err
+		}
+		loc.Longitude, loc.Latitude = pos.Longitude, pos.Latitude
+	}
+	return loc, nil
+}
+
+func (cmd *GeoLocationCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *GeoLocationCmd) Result() ([]GeoLocation, error) {
+	return cmd.result, cmd.err
+}
+
+func parseGeoPos(vals []interface{}) (*GeoPos, error) {
+	if len(vals) != 2 {
+		return nil, fmt.Errorf("redis: got %d elements in geo position, want 2", len(vals))
+	}
+	lon, err := toFloat64(vals[0])
+	if err != nil {
+		return nil, err
+	}
+	lat, err := toFloat64(vals[1])
+	if err != nil {
+		return nil, err
+	}
+	return &GeoPos{
+		Longitude: lon,
+		Latitude:  lat,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package go_redis
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGeoArgs(t *testing.T) {
+	var args []interface{}
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		args = cmd.Args()
+		return nil
+	})
+	ctx := context.Background()
+
+	_ = c.GeoAdd(ctx, "shops", &GeoAddOptions{XX: true, CH: true}, &GeoLocation{Name: "a", Longitude: 13.4, Latitude: 52.5})
+	if want := []interface{}{"GEOADD", "shops", "xx", "ch", 13.4, 52.5, "a"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+
+	_ = c.GeoSearch(ctx, "shops", &GeoSearchQuery{
+		Member: "a", Radius: 5, Sort: "ASC", Count: 3, CountAny: true, WithCoord: true, WithDist: true,
+	})
+	want := []interface{}{"GEOSEARCH", "shops", "frommember", "a", "byradius", 5.0, "km", "asc", "count", 3, "any", "withcoord", "withdist"}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+
+	_ = c.GeoSearchStore(ctx, "shops", "near", &GeoSearchStoreQuery{
+		GeoSearchQuery: GeoSearchQuery{Longitude: 1, Latitude: 2, BoxWidth: 3, BoxHeight: 4, Unit: "m"},
+		StoreDist:      true,
+	})
+	want = []interface{}{"GEOSEARCHSTORE", "near", "shops", "fromlonlat", 1.0, 2.0, "bybox", 3.0, 4.0, "m", "storedist"}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+
+	// invalid arguments fail without being sent
+	args = nil
+	if err := c.GeoAdd(ctx, "shops", nil, nil).Err(); err == nil {
+		t.Fatal("nil location accepted")
+	}
+	if err := c.GeoSearch(ctx, "shops", nil).Err(); err == nil {
+		t.Fatal("nil query accepted")
+	}
+	if err := c.GeoSearchStore(ctx, "shops", "near", nil).Err(); err == nil {
+		t.Fatal("nil query accepted")
+	}
+	if args != nil {
+		t.Fatalf("sent %v", args)
+	}
+}
+
+func TestGeoLocationParse(t *testing.T) {
+	coord := []interface{}{"13.4", "52.5"}
+	for _, tt := range []struct {
+		q     GeoSearchQuery
+		reply interface{}
+		want  GeoLocation
+	}{
+		{GeoSearchQuery{}, "a", GeoLocation{Name: "a"}},
+		{GeoSearchQuery{WithDist: true}, []interface{}{"a", "1.5"}, GeoLocation{Name: "a", Dist: 1.5}},
+		{GeoSearchQuery{WithHash: true}, []interface{}{"a", int64(42)}, GeoLocation{Name: "a", GeoHash: 42}},
+		{GeoSearchQuery{WithCoord: true}, []interface{}{"a", coord}, GeoLocation{Name: "a", Longitude: 13.4, Latitude: 52.5}},
+		{GeoSearchQuery{WithDist: true, WithHash: true}, []interface{}{"a", "1.5", int64(42)}, GeoLocation{Name: "a", Dist: 1.5, GeoHash: 42}},
+		{GeoSearchQuery{WithDist: true, WithCoord: true}, []interface{}{"a", "1.5", coord}, GeoLocation{Name: "a", Dist: 1.5, Longitude: 13.4, Latitude: 52.5}},
+		{GeoSearchQuery{WithHash: true, WithCoord: true}, []interface{}{"a", int64(42), coord}, GeoLocation{Name: "a", GeoHash: 42, Longitude: 13.4, Latitude: 52.5}},
+		// resp3 doubles
+		{GeoSearchQuery{WithDist: true, WithHash: true, WithCoord: true}, []interface{}{"a", 1.5, int64(42), []interface{}{13.4, 52.5}},
+			GeoLocation{Name: "a", Dist: 1.5, GeoHash: 42, Longitude: 13.4, Latitude: 52.5}},
+	} {
+		cmd := &GeoLocationCmd{baseCmd: &baseCmd{}, q: &tt.q}
+		if err := cmd.ReadReply([]interface{}{tt.reply}); err != nil {
+			t.Fatalf("%+v: %v", tt.q, err)
+		}
+		if got, _ := cmd.Result(); len(got) != 1 || got[0] != tt.want {
+			t.Errorf("%+v: got %+v, want %+v", tt.q, got, tt.want)
+		}
+	}
+
+	// a field missing from the reply
+	cmd := &GeoLocationCmd{baseCmd: &baseCmd{}, q: &GeoSearchQuery{WithDist: true, WithCoord: true}}
+	if err := cmd.ReadReply([]interface{}{[]interface{}{"a", "1.5"}}); err == nil {
+		t.Fatal("want error for missing coord")
+	}
+}
+
+func TestGeoPosMissing(t *testing.T) {
+	cmd := &GeoPosCmd{baseCmd: &baseCmd{}}
+	if err := cmd.ReadReply([]interface{}{[]interface{}{"13.4", "52.5"}, nil}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := cmd.Result()
+	if len(got) != 2 || got[1] != nil || *got[0] != (GeoPos{Longitude: 13.4, Latitude: 52.5}) {
+		t.Fatalf("got %v", got)
+	}
+	if err := cmd.ReadReply([]interface{}{[]interface{}{"13.4"}}); err == nil {
+		t.Fatal("want error for short position")
+	}
+}
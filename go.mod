@@ -1,6 +1,6 @@
 module github.com/mingolm/go-redis
 
-go 1.18
+go 1.23
 
 require go.uber.org/zap v1.23.0
 
@@ -0,0 +1,60 @@
+package go_redis
+
+import (
+	"context"
+	"github.com/mingolm/go-redis/proto"
+)
+
+type HashCmdable interface {
+	HSet(ctx context.Context, key string, values ...interface{}) *IntCmd
+	HGet(ctx context.Context, key, field string) *StringCmd
+	HMGet(ctx context.Context, key string, fields ...string) *SliceCmd
+	HGetAll(ctx context.Context, key string) *MapStringStringCmd
+}
+
+// HSet sets fields of a hash and returns the number of fields added.
+// values are flattened with proto.AppendArgs:
+//
+//	HSet(ctx, "user:1", "name", "mingo", "age", 18)
+//	HSet(ctx, "user:1", map[string]interface{}{"name": "mingo", "age": 18})
+//	HSet(ctx, "user:1", User{Name: "mingo", Age: 18}) // `redis:"name"` tags
+func (c cmdable) HSet(ctx context.Context, key string, values ...interface{}) *IntCmd {
+	args := make([]interface{}, 2, 2+2*len(values))
+	args[0] = "HSET"
+	args[1] = key
+	args = proto.AppendArgs(args, values...)
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) HGet(ctx context.Context, key, field string) *StringCmd {
+	cmd := newStringCmd(ctx, "HGET", key, field)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// HMGet returns the values of fields, nil for the missing ones.
+// The reply can be scanned into a struct with SliceCmd.Scan.
+func (c cmdable) HMGet(ctx context.Context, key string, fields ...string) *SliceCmd {
+	args := make([]interface{}, 2, 2+len(fields))
+	args[0] = "HMGET"
+	args[1] = key
+	for _, field := range fields {
+		args = append(args, field)
+	}
+	cmd := newSliceCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// HGetAll returns every field of a hash. The reply can be scanned into a
+// struct with MapStringStringCmd.Scan:
+//
+//	var u User
+//	err := redis.HGetAll(ctx, "user:1").Scan(&u)
+func (c cmdable) HGetAll(ctx context.Context, key string) *MapStringStringCmd {
+	cmd := newMapStringStringCmd(ctx, "HGETALL", key)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
@@ -0,0 +1,35 @@
+package go_redis
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// Only the values of key/value commands are flattened, other arguments
+// are sent as given.
+func TestFlattenedArgs(t *testing.T) {
+	var args []interface{}
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		args = cmd.Args()
+		return nil
+	})
+	ctx := context.Background()
+
+	type user struct {
+		Name string `redis:"name"`
+		Age  int    `redis:"age,omitempty"`
+	}
+	c.HSet(ctx, "user:1", user{Name: "mingo"}, "tags", []string{"a"})
+	if want := []interface{}{"HSET", "user:1", "name", "mingo", "tags", "a"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+	c.MSet(ctx, []string{"k1", "v1"}, "k2", 2)
+	if want := []interface{}{"MSET", "k1", "v1", "k2", 2}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+	c.Set(ctx, "k", []string{"v", "NX"}, 0)
+	if want := []interface{}{"SET", "k", []string{"v", "NX"}}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+}
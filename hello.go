@@ -0,0 +1,113 @@
+package go_redis
+
+import (
+	"fmt"
+)
+
+// HelloInfo is the server's reply to HELLO.
+type HelloInfo struct {
+	Server  string
+	Version string
+	// negotiated protocol, 2 or 3
+	Proto int
+	// client id of the connection
+	ID      int64
+	Mode    string
+	Role    string
+	Modules []ModuleInfo
+}
+
+type ModuleInfo struct {
+	Name    string
+	Version int64
+	Path    string
+	Args    []string
+}
+
+type HelloCmd struct {
+	*baseCmd
+	result *HelloInfo
+}
+
+func (cmd *HelloCmd) ReadReply(val interface{}) error {
+	pairs, err := toPairs(val)
+	if err != nil {
+		return err
+	}
+	info := &HelloInfo{}
+	for i := 0; i < len(pairs); i += 2 {
+		k, err := toString(pairs[i])
+		if err != nil {
+			return err
+		}
+		v := pairs[i+1]
+		switch k {
+		case "server":
+			info.Server, err = toString(v)
+		case "version":
+			info.Version, err = toString(v)
+		case "proto":
+			var n int64
+			n, err = toInt64(v)
+			info.Proto = int(n)
+		case "id":
+			info.ID, err = toInt64(v)
+		case "mode":
+			info.Mode, err = toString(v)
+		case "role":
+			info.Role, err = toString(v)
+		case "modules":
+			var modules []interface{}
+			if modules, err = toSlice(v); err != nil {
+				break
+			}
+			info.Modules = make([]ModuleInfo, len(modules))
+			for j, m := range modules {
+				if info.Modules[j], err = parseModuleInfo(m); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	cmd.result = info
+	return nil
+}
+
+func parseModuleInfo(val interface{}) (m ModuleInfo, err error) {
+	pairs, err := toPairs(val)
+	if err != nil {
+		return m, err
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		k, err := toString(pairs[i])
+		if err != nil {
+			return m, err
+		}
+		v := pairs[i+1]
+		switch k {
+		case "name":
+			m.Name, err = toString(v)
+		case "ver":
+			m.Version, err = toInt64(v)
+		case "path":
+			m.Path, err = toString(v)
+		case "args":
+			m.Args, err = toStrings(v)
+		}
+		if err != nil {
+			return m, err
+		}
+	}
+	return m, nil
+}
+
+func (cmd *HelloCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *HelloCmd) Result() (*HelloInfo, error) {
+	return cmd.result, cmd.err
+}
@@ -0,0 +1,41 @@
+package go_redis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHelloCmd(t *testing.T) {
+	want := &HelloInfo{
+		Server:  "redis",
+		Version: "7.2.4",
+		Proto:   3,
+		ID:      12,
+		Mode:    "standalone",
+		Role:    "master",
+		Modules: []ModuleInfo{{Name: "search", Version: 21005, Path: "/usr/lib/redis/modules/search.so", Args: []string{}}},
+	}
+
+	module := []interface{}{"name", "search", "ver", int64(21005), "path", "/usr/lib/redis/modules/search.so", "args", []interface{}{}}
+	replies := map[string]interface{}{
+		"resp2": []interface{}{
+			"server", "redis", "version", "7.2.4", "proto", int64(3), "id", int64(12),
+			"mode", "standalone", "role", "master", "modules", []interface{}{module},
+		},
+		"resp3": map[interface{}]interface{}{
+			"server": "redis", "version": "7.2.4", "proto": int64(3), "id": int64(12),
+			"mode": "standalone", "role": "master", "modules": []interface{}{
+				map[interface{}]interface{}{"name": "search", "ver": int64(21005), "path": "/usr/lib/redis/modules/search.so", "args": []interface{}{}},
+			},
+		},
+	}
+	for name, reply := range replies {
+		cmd := &HelloCmd{baseCmd: &baseCmd{}}
+		if err := cmd.ReadReply(reply); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got, _ := cmd.Result(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %+v, want %+v", name, got, want)
+		}
+	}
+}
@@ -2,6 +2,10 @@ package go_redis
 
 import (
 	"context"
+	"fmt"
+	"github.com/mingolm/go-redis/proto"
+	"math/big"
+	"strconv"
 	"time"
 )
 
@@ -9,6 +13,26 @@ func usePrecise(dur time.Duration) bool {
 	return dur < time.Second || dur%time.Second != 0
 }
 
+// retryBackoff is exponential from 8ms, capped at 512ms.
+func retryBackoff(attempt int) time.Duration {
+	if attempt > 7 {
+		attempt = 7
+	}
+	return 8 * time.Millisecond << (attempt - 1)
+}
+
+func sleep(ctx context.Context, dur time.Duration) error {
+	t := time.NewTimer(dur)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func formatMs(ctx context.Context, dur time.Duration) int64 {
 	if dur > 0 && dur < time.Millisecond {
 		return 1
@@ -22,3 +46,121 @@ func formatSec(ctx context.Context, dur time.Duration) int64 {
 	}
 	return int64(dur / time.Second)
 }
+
+// toInt64 converts an integer reply (or its bulk string form) to int64.
+func toInt64(val interface{}) (int64, error) {
+	switch v := val.(type) {
+	case int64:
+		return v, nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	case bool:
+		// resp3 boolean of commands replying 0/1 in resp2
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case *big.Int:
+		if !v.IsInt64() {
+			return 0, fmt.Errorf("redis: %s overflows int64", v)
+		}
+		return v.Int64(), nil
+	default:
+		return 0, fmt.Errorf("redis: unexpected reply type %T, want int", val)
+	}
+}
+
+// toFloat64 converts a double reply (or its bulk string form) to float64.
+func toFloat64(val interface{}) (float64, error) {
+	switch v := val.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("redis: unexpected reply type %T, want float", val)
+	}
+}
+
+func toString(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case string:
+		return v, nil
+	case proto.VerbatimString:
+		return v.Text, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		// resp3 double of commands replying a bulk string in resp2
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("redis: unexpected reply type %T, want string", val)
+	}
+}
+
+func toSlice(val interface{}) ([]interface{}, error) {
+	switch v := val.(type) {
+	case []interface{}:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply type %T, want array", val)
+	}
+}
+
+// toPairs flattens a resp3 map or a resp2 key/value array into
+// k1, v1, k2, v2, ...
+func toPairs(val interface{}) ([]interface{}, error) {
+	switch v := val.(type) {
+	case []interface{}:
+		if len(v)%2 != 0 {
+			return nil, fmt.Errorf("redis: got %d elements in key/value reply, want even", len(v))
+		}
+		return v, nil
+	case map[interface{}]interface{}:
+		pairs := make([]interface{}, 0, 2*len(v))
+		for k, e := range v {
+			pairs = append(pairs, k, e)
+		}
+		return pairs, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply type %T, want map", val)
+	}
+}
+
+func toStrings(val interface{}) ([]string, error) {
+	vals, err := toSlice(val)
+	if err != nil {
+		return nil, err
+	}
+	ss := make([]string, len(vals))
+	for i, v := range vals {
+		if ss[i], err = toString(v); err != nil {
+			return nil, err
+		}
+	}
+	return ss, nil
+}
+
+func toStringMap(val interface{}) (map[string]string, error) {
+	pairs, err := toPairs(val)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		k, err := toString(pairs[i])
+		if err != nil {
+			return nil, err
+		}
+		if pairs[i+1] == nil {
+			m[k] = ""
+			continue
+		}
+		if m[k], err = toString(pairs[i+1]); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
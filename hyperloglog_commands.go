@@ -0,0 +1,42 @@
+package go_redis
+
+import "context"
+
+type HyperLogLogCmdable interface {
+	PFAdd(ctx context.Context, key string, elements ...interface{}) *IntCmd
+	PFCount(ctx context.Context, keys ...string) *IntCmd
+	PFMerge(ctx context.Context, destKey string, keys ...string) *StatusCmd
+}
+
+func (c cmdable) PFAdd(ctx context.Context, key string, elements ...interface{}) *IntCmd {
+	args := make([]interface{}, 2, 2+len(elements))
+	args[0] = "PFADD"
+	args[1] = key
+	args = append(args, elements...)
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) PFCount(ctx context.Context, keys ...string) *IntCmd {
+	args := make([]interface{}, 1, 1+len(keys))
+	args[0] = "PFCOUNT"
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) PFMerge(ctx context.Context, destKey string, keys ...string) *StatusCmd {
+	args := make([]interface{}, 2, 2+len(keys))
+	args[0] = "PFMERGE"
+	args[1] = destKey
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	cmd := newStatusCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
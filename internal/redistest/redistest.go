@@ -0,0 +1,106 @@
+// Package redistest serves in-memory connections to a fake Redis server,
+// for the tests of the packages built on the client.
+package redistest
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	go_redis "github.com/mingolm/go-redis"
+	"github.com/mingolm/go-redis/proto"
+	"net"
+	"strings"
+)
+
+// Handler replies to the command args on cn. It is called concurrently
+// for distinct connections.
+type Handler func(cn net.Conn, args []string)
+
+// Dialer dials in-memory connections served by handle.
+func Dialer(handle Handler) func(ctx context.Context) (net.Conn, error) {
+	return func(ctx context.Context) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			rd := proto.NewReader(bufio.NewReader(server))
+			for {
+				val, err := rd.Read()
+				if err != nil {
+					return
+				}
+				var args []string
+				for _, v := range val.([]interface{}) {
+					args = append(args, v.(string))
+				}
+				handle(server, args)
+			}
+		}()
+		return client, nil
+	}
+}
+
+// NewClient returns a RESP2 client of handle, which is not passed the
+// commands setting up the connections.
+func NewClient(handle Handler) *go_redis.Redis {
+	return go_redis.NewClient(&go_redis.Options{
+		Dialer: Dialer(func(cn net.Conn, args []string) {
+			switch strings.ToUpper(args[0]) {
+			case "HELLO":
+				Error(cn, "ERR unknown command 'HELLO'")
+			case "CLIENT":
+				Status(cn, "OK")
+			default:
+				handle(cn, args)
+			}
+		}),
+		PoolSize:     4,
+		MinIdleConns: 1,
+		MaxIdleConns: 4,
+	})
+}
+
+func Status(cn net.Conn, s string) {
+	fmt.Fprintf(cn, "+%s\r\n", s)
+}
+
+func Error(cn net.Conn, msg string) {
+	fmt.Fprintf(cn, "-%s\r\n", msg)
+}
+
+func Int(cn net.Conn, n int64) {
+	fmt.Fprintf(cn, ":%d\r\n", n)
+}
+
+func Bulk(cn net.Conn, s string) {
+	fmt.Fprintf(cn, "$%d\r\n%s\r\n", len(s), s)
+}
+
+func Nil(cn net.Conn) {
+	fmt.Fprint(cn, "$-1\r\n")
+}
+
+// NilArray replies the null array of the commands replying arrays, such
+// as a BLPOP timing out.
+func NilArray(cn net.Conn) {
+	fmt.Fprint(cn, "*-1\r\n")
+}
+
+// Strings replies an array of bulk strings.
+func Strings(cn net.Conn, ss ...string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(ss))
+	for _, s := range ss {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(s), s)
+	}
+	fmt.Fprint(cn, b.String())
+}
+
+// Ints replies an array of integers.
+func Ints(cn net.Conn, ns ...int64) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(ns))
+	for _, n := range ns {
+		fmt.Fprintf(&b, ":%d\r\n", n)
+	}
+	fmt.Fprint(cn, b.String())
+}
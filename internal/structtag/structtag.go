@@ -0,0 +1,49 @@
+// Package structtag parses the `redis:"name"` struct tags, shared by the
+// argument writer of package proto and the reply scanner, so that both
+// agree on the names of the fields.
+package structtag
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Field is a struct field tagged `redis:"name"` or `redis:"name,omitempty"`.
+// An empty name is the name of the field.
+type Field struct {
+	Index     int
+	Name      string
+	OmitEmpty bool
+}
+
+var cache sync.Map // reflect.Type -> []Field
+
+// Fields lists the exported fields of t tagged with `redis:"..."`, but
+// `redis:"-"`.
+func Fields(t reflect.Type) []Field {
+	if fields, ok := cache.Load(t); ok {
+		return fields.([]Field)
+	}
+
+	var fields []Field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("redis")
+		if !ok || tag == "-" || !sf.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, Field{
+			Index:     i,
+			Name:      name,
+			OmitEmpty: opts == "omitempty",
+		})
+	}
+
+	cache.Store(t, fields)
+	return fields
+}
@@ -0,0 +1,31 @@
+package go_redis
+
+import "context"
+
+type KeyCmdable interface {
+	Del(ctx context.Context, keys ...string) *IntCmd
+	Exists(ctx context.Context, keys ...string) *IntCmd
+}
+
+func keysArgs(name string, keys []string) []interface{} {
+	args := make([]interface{}, 1, 1+len(keys))
+	args[0] = name
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	return args
+}
+
+// Del returns the number of keys removed.
+func (c cmdable) Del(ctx context.Context, keys ...string) *IntCmd {
+	cmd := newIntCmd(ctx, keysArgs("DEL", keys)...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// Exists returns how many of keys exist, counting repeated keys again.
+func (c cmdable) Exists(ctx context.Context, keys ...string) *IntCmd {
+	cmd := newIntCmd(ctx, keysArgs("EXISTS", keys)...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
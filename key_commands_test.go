@@ -0,0 +1,61 @@
+package go_redis
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKeyCommands(t *testing.T) {
+	var args []interface{}
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		args = cmd.Args()
+		return cmd.ReadReply(int64(2))
+	})
+	ctx := context.Background()
+
+	if n, err := c.Del(ctx, "a", "b").Result(); err != nil || n != 2 {
+		t.Fatalf("got %d, %v", n, err)
+	}
+	if want := []interface{}{"DEL", "a", "b"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+	c.Exists(ctx, "a", "a")
+	if want := []interface{}{"EXISTS", "a", "a"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+	c.SetNX(ctx, "lock", "token", 1500*time.Millisecond)
+	if want := []interface{}{"SET", "lock", "token", "nx", "px", int64(1500)}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+	c.SetNX(ctx, "lock", "token", 0)
+	if want := []interface{}{"SET", "lock", "token", "nx"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+}
+
+func TestBoolCmd(t *testing.T) {
+	for _, tt := range []struct {
+		reply interface{}
+		want  bool
+	}{
+		{"OK", true},
+		{nil, false},
+		{int64(1), true},
+		{int64(0), false},
+		{true, true},
+		{false, false},
+	} {
+		cmd := newBoolCmd(context.Background())
+		if err := cmd.ReadReply(tt.reply); err != nil {
+			t.Fatalf("%v: %v", tt.reply, err)
+		}
+		if got, _ := cmd.Result(); got != tt.want {
+			t.Errorf("%v: got %v, want %v", tt.reply, got, tt.want)
+		}
+	}
+	if err := newBoolCmd(context.Background()).ReadReply(1.5); err == nil {
+		t.Fatal("want error for a double")
+	}
+}
@@ -0,0 +1,122 @@
+package go_redis
+
+import (
+	"context"
+	"time"
+)
+
+type ListCmdable interface {
+	LPush(ctx context.Context, key string, values ...interface{}) *IntCmd
+	RPush(ctx context.Context, key string, values ...interface{}) *IntCmd
+	LPop(ctx context.Context, key string) *StringCmd
+	RPop(ctx context.Context, key string) *StringCmd
+	LLen(ctx context.Context, key string) *IntCmd
+	LRange(ctx context.Context, key string, start, stop int64) *StringSliceCmd
+	LRem(ctx context.Context, key string, count int64, value interface{}) *IntCmd
+	LMove(ctx context.Context, source, destination, srcPos, destPos string) *StringCmd
+	BLPop(ctx context.Context, timeout time.Duration, keys ...string) *StringSliceCmd
+	BRPop(ctx context.Context, timeout time.Duration, keys ...string) *StringSliceCmd
+	BLMove(ctx context.Context, source, destination, srcPos, destPos string, timeout time.Duration) *StringCmd
+}
+
+// List ends of LMOVE and BLMOVE.
+const (
+	Left  = "LEFT"
+	Right = "RIGHT"
+)
+
+// LPush returns the length of the list after the push.
+func (c cmdable) LPush(ctx context.Context, key string, values ...interface{}) *IntCmd {
+	args := make([]interface{}, 2, 2+len(values))
+	args[0] = "LPUSH"
+	args[1] = key
+	args = append(args, values...)
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// RPush returns the length of the list after the push.
+func (c cmdable) RPush(ctx context.Context, key string, values ...interface{}) *IntCmd {
+	args := make([]interface{}, 2, 2+len(values))
+	args[0] = "RPUSH"
+	args[1] = key
+	args = append(args, values...)
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) LPop(ctx context.Context, key string) *StringCmd {
+	cmd := newStringCmd(ctx, "LPOP", key)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) RPop(ctx context.Context, key string) *StringCmd {
+	cmd := newStringCmd(ctx, "RPOP", key)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) LLen(ctx context.Context, key string) *IntCmd {
+	cmd := newIntCmd(ctx, "LLEN", key)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) LRange(ctx context.Context, key string, start, stop int64) *StringSliceCmd {
+	cmd := newStringSliceCmd(ctx, "LRANGE", key, start, stop)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// LRem removes count occurrences of value from the head (count > 0), from
+// the tail (count < 0) or all of them (count = 0), and returns how many.
+func (c cmdable) LRem(ctx context.Context, key string, count int64, value interface{}) *IntCmd {
+	cmd := newIntCmd(ctx, "LREM", key, count, value)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// LMove pops from the srcPos end (Left or Right) of source and pushes to
+// the destPos end of destination, returning the element.
+func (c cmdable) LMove(ctx context.Context, source, destination, srcPos, destPos string) *StringCmd {
+	cmd := newStringCmd(ctx, "LMOVE", source, destination, srcPos, destPos)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// blockingTimeout formats the timeout of blocking commands, in seconds
+// with decimals since Redis 6.0; 0 blocks indefinitely.
+func blockingTimeout(timeout time.Duration) interface{} {
+	if timeout%time.Second == 0 {
+		return int64(timeout / time.Second)
+	}
+	return timeout.Seconds()
+}
+
+// BLPop pops from the first non-empty list of keys, waiting up to timeout,
+// and returns the key and the element. It returns proto.Nil on timeout.
+func (c cmdable) BLPop(ctx context.Context, timeout time.Duration, keys ...string) *StringSliceCmd {
+	args := append(keysArgs("BLPOP", keys), blockingTimeout(timeout))
+	cmd := newStringSliceCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// BRPop is BLPop popping from the tail.
+func (c cmdable) BRPop(ctx context.Context, timeout time.Duration, keys ...string) *StringSliceCmd {
+	args := append(keysArgs("BRPOP", keys), blockingTimeout(timeout))
+	cmd := newStringSliceCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// BLMove is LMove waiting up to timeout for source to be non-empty. It
+// returns proto.Nil on timeout.
+func (c cmdable) BLMove(ctx context.Context, source, destination, srcPos, destPos string, timeout time.Duration) *StringCmd {
+	cmd := newStringCmd(ctx, "BLMOVE", source, destination, srcPos, destPos, blockingTimeout(timeout))
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
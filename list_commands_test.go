@@ -0,0 +1,36 @@
+package go_redis
+
+import (
+	"context"
+	"github.com/mingolm/go-redis/proto"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBlockingCommands(t *testing.T) {
+	var args []interface{}
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		args = cmd.Args()
+		// timed out
+		return proto.Nil
+	})
+	ctx := context.Background()
+
+	if err := c.BLPop(ctx, 1500*time.Millisecond, "a", "b").Err(); err != proto.Nil {
+		t.Fatalf("got %v, want Nil", err)
+	}
+	if want := []interface{}{"BLPOP", "a", "b", 1.5}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+
+	_ = c.BLMove(ctx, "src", "dst", Right, Left, 2*time.Second)
+	if want := []interface{}{"BLMOVE", "src", "dst", Right, Left, int64(2)}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+
+	_ = c.BRPop(ctx, 0, "q")
+	if want := []interface{}{"BRPOP", "q", int64(0)}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+}
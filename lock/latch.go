@@ -0,0 +1,116 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	go_redis "github.com/mingolm/go-redis"
+	"github.com/mingolm/go-redis/proto"
+	"time"
+)
+
+// countDownScript creates the counter on first use, decrements it and
+// signals the waiters when it reaches zero.
+var countDownScript = go_redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+end
+local n = redis.call("DECR", KEYS[1])
+if n == 0 then
+	redis.call("RPUSH", KEYS[2], 1)
+	redis.call("PEXPIRE", KEYS[2], redis.call("PTTL", KEYS[1]))
+end
+if n < 0 then
+	redis.call("INCR", KEYS[1])
+	n = 0
+end
+return n
+`)
+
+// resignalScript puts back the signal a waiter popped, for the others.
+var resignalScript = go_redis.NewScript(`
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl > 0 then
+	redis.call("RPUSH", KEYS[2], 1)
+	redis.call("PEXPIRE", KEYS[2], ttl)
+end
+return ttl
+`)
+
+// latchPoll bounds a blocking wait, for ctx to be checked.
+const latchPoll = time.Second
+
+// Latch lets processes wait until others counted it down to zero:
+//
+//	latch := lock.NewLatch(redis, "deploy:ready", 3, time.Hour)
+//	// in each of the 3 workers
+//	latch.CountDown(ctx)
+//	// in the coordinator
+//	err := latch.Wait(ctx)
+//
+// The latch is created by the first CountDown and expires ttl later,
+// after which it starts over.
+type Latch struct {
+	rdb   go_redis.Cmdable
+	key   string
+	count int
+	ttl   time.Duration
+}
+
+func NewLatch(rdb go_redis.Cmdable, key string, count int, ttl time.Duration) *Latch {
+	return &Latch{
+		rdb:   rdb,
+		key:   key,
+		count: count,
+		ttl:   ttl,
+	}
+}
+
+func (l *Latch) signalKey() string {
+	return l.key + ":signal"
+}
+
+// CountDown decrements the count and returns it, never below zero.
+func (l *Latch) CountDown(ctx context.Context) (int64, error) {
+	return countDownScript.Run(ctx, l.rdb, []string{l.key, l.signalKey()}, l.count, l.ttl.Milliseconds()).Int64()
+}
+
+// Count returns the current count.
+func (l *Latch) Count(ctx context.Context) (int64, error) {
+	n, err := l.rdb.Get(ctx, l.key).Int64()
+	if errors.Is(err, proto.Nil) {
+		return int64(l.count), nil
+	}
+	return n, err
+}
+
+// Wait blocks until the count is zero, or ctx is done.
+func (l *Latch) Wait(ctx context.Context) error {
+	for {
+		n, err := l.Count(ctx)
+		if err != nil {
+			return err
+		}
+		if n <= 0 {
+			return nil
+		}
+
+		timeout := latchPoll
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = min(timeout, time.Until(deadline))
+		}
+		if timeout < time.Millisecond {
+			return context.DeadlineExceeded
+		}
+
+		err = l.rdb.BLPop(ctx, timeout, l.signalKey()).Err()
+		if err == nil {
+			return resignalScript.Run(ctx, l.rdb, []string{l.key, l.signalKey()}).Err()
+		}
+		if !errors.Is(err, proto.Nil) {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
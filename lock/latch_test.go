@@ -0,0 +1,139 @@
+package lock
+
+import (
+	"context"
+	"github.com/mingolm/go-redis/internal/redistest"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLatch runs the latch scripts on a counter and a signal list in
+// memory.
+type fakeLatch struct {
+	mu      sync.Mutex
+	exists  bool
+	count   int64
+	signals int
+	blocked int
+}
+
+func (f *fakeLatch) handle(cn net.Conn, args []string) {
+	if args[0] == "BLPOP" {
+		f.mu.Lock()
+		f.blocked++
+		f.mu.Unlock()
+		timeout, _ := strconv.ParseFloat(args[2], 64)
+		deadline := time.Now().Add(time.Duration(timeout * float64(time.Second)))
+		for time.Now().Before(deadline) {
+			f.mu.Lock()
+			if f.signals > 0 {
+				f.signals--
+				f.mu.Unlock()
+				redistest.Strings(cn, args[1], "1")
+				return
+			}
+			f.mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+		}
+		redistest.NilArray(cn)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case args[0] == "GET":
+		if !f.exists {
+			redistest.Nil(cn)
+			return
+		}
+		redistest.Bulk(cn, strconv.FormatInt(f.count, 10))
+	case args[0] == "EVALSHA" && args[1] == countDownScript.Hash():
+		// EVALSHA sha 2 key signal count ttl
+		if !f.exists {
+			f.exists = true
+			f.count, _ = strconv.ParseInt(args[5], 10, 64)
+		}
+		f.count--
+		if f.count == 0 {
+			f.signals++
+		}
+		if f.count < 0 {
+			f.count = 0
+		}
+		redistest.Int(cn, f.count)
+	case args[0] == "EVALSHA" && args[1] == resignalScript.Hash():
+		if !f.exists {
+			redistest.Int(cn, -2)
+			return
+		}
+		f.signals++
+		redistest.Int(cn, 1000)
+	default:
+		redistest.Error(cn, "NOSCRIPT No matching script")
+	}
+}
+
+func TestLatch(t *testing.T) {
+	f := &fakeLatch{}
+	latch := NewLatch(redistest.NewClient(f.handle), "ready", 3, time.Hour)
+	ctx := context.Background()
+
+	if n, err := latch.Count(ctx); err != nil || n != 3 {
+		t.Fatalf("got %d, %v before any count down", n, err)
+	}
+
+	// both waiters are woken by the single signal, put back by each
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			errs <- latch.Wait(ctx)
+		}()
+	}
+	for {
+		f.mu.Lock()
+		blocked := f.blocked
+		f.mu.Unlock()
+		if blocked == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	for _, want := range []int64{2, 1, 0, 0} {
+		n, err := latch.CountDown(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != want {
+			t.Fatalf("got count %d, want %d", n, want)
+		}
+	}
+	for range 2 {
+		select {
+		case err := <-errs:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("waiter not woken")
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.signals != 1 {
+		t.Fatalf("got %d signals left, want 1", f.signals)
+	}
+}
+
+func TestLatchWaitTimeout(t *testing.T) {
+	latch := NewLatch(redistest.NewClient((&fakeLatch{}).handle), "ready", 1, time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := latch.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want DeadlineExceeded", err)
+	}
+}
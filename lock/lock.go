@@ -0,0 +1,325 @@
+// Package lock implements a distributed lock on a Redis key, held with a
+// random token and an expiring lease:
+//
+//	l, err := lock.New(redis).Obtain(ctx, "jobs:cleanup", 10*time.Second, &lock.Options{
+//		RetryStrategy: lock.LimitRetry(lock.LinearBackoff(100*time.Millisecond), 10),
+//		AutoRefresh:   true,
+//	})
+//	if errors.Is(err, lock.ErrNotObtained) {
+//	}
+//	defer l.Release(ctx)
+//
+// Each acquisition also gets a fencing token, increasing with every
+// acquisition of the key, which storage written under the lock can use to
+// reject writes of a holder whose lease expired.
+//
+// NewRedlock holds the same locks on several independent servers, so that
+// the loss of a minority of them doesn't lose or duplicate the lock.
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	go_redis "github.com/mingolm/go-redis"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotObtained is returned by Obtain when the lock is held by
+	// someone else.
+	ErrNotObtained = errors.New("lock: not obtained")
+	// ErrNotHeld is returned when the lease expired or was released.
+	ErrNotHeld = errors.New("lock: not held")
+)
+
+// obtainScript sets the lock and increments the fencing counter, which
+// never expires. It returns 0 if the lock is held.
+var obtainScript = go_redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+var refreshScript = go_redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+var releaseScript = go_redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// ttlScript returns -3 if the lock is not held with the token.
+var ttlScript = go_redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PTTL", KEYS[1])
+end
+return -3
+`)
+
+// Locker is implemented by the clients of New and NewRedlock.
+type Locker interface {
+	Obtain(ctx context.Context, key string, ttl time.Duration, opt *Options) (*Lock, error)
+}
+
+type Client struct {
+	nodes nodes
+}
+
+var _ Locker = (*Client)(nil)
+
+// New returns a client locking on a single server.
+func New(rdb go_redis.Cmdable) *Client {
+	return &Client{nodes: nodes{rdb}}
+}
+
+func fenceKey(key string) string {
+	return key + ":fence"
+}
+
+func newToken() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// Obtain acquires the lock on key for ttl, retrying according to
+// opt.RetryStrategy. It returns ErrNotObtained if the lock is still held
+// by someone else when giving up or when ctx is done.
+func (c *Client) Obtain(ctx context.Context, key string, ttl time.Duration, opt *Options) (*Lock, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	var l *Lock
+	if err := retry(ctx, opt.RetryStrategy, func() (err error) {
+		l, err = c.obtain(ctx, key, token, ttl)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if opt.AutoRefresh {
+		go watch(l.done, ttl, l.Refresh, l.close)
+	}
+	return l, nil
+}
+
+// retry calls try while it returns ErrNotHeld, and returns ErrNotObtained
+// when strategy gives up or ctx is done.
+func retry(ctx context.Context, strategy RetryStrategy, try func() error) error {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			backoff := strategy(attempt)
+			if backoff <= 0 {
+				return ErrNotObtained
+			}
+			if err := sleep(ctx, backoff); err != nil {
+				return ErrNotObtained
+			}
+		}
+
+		if err := try(); !errors.Is(err, ErrNotHeld) {
+			return err
+		}
+	}
+}
+
+// obtain makes one attempt on every node, and returns ErrNotHeld if the
+// lock is held elsewhere.
+func (c *Client) obtain(ctx context.Context, key, token string, ttl time.Duration) (*Lock, error) {
+	l := newLock(c.nodes, key, token)
+	redlock := len(c.nodes) > 1
+
+	// a hung node must not hold the attempt past the lease
+	if redlock {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, nodeTimeout(ttl))
+		defer cancel()
+	}
+
+	start := time.Now()
+	var mu sync.Mutex
+	ok, notOK, err := c.nodes.each(ctx, func(ctx context.Context, _ int, rdb go_redis.Cmdable) (bool, error) {
+		fence, err := obtainScript.Run(ctx, rdb, []string{key, fenceKey(key)}, token, ttl.Milliseconds()).Int64()
+		if err != nil || fence == 0 {
+			return false, err
+		}
+		mu.Lock()
+		l.fence = max(l.fence, fence)
+		mu.Unlock()
+		return true, nil
+	})
+	err = c.nodes.verdict(ok, notOK, err)
+
+	// the leases taken first run out while the others are taken
+	if err == nil && redlock && time.Since(start)+drift(ttl) >= ttl {
+		err = ErrNotHeld
+	}
+	// nodes that failed or timed out may have granted the lock all the same
+	if err != nil && redlock {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), nodeTimeout(ttl))
+		_ = l.Release(ctx)
+		cancel()
+	}
+	return l, err
+}
+
+// nodeTimeout is how long an attempt waits for the nodes, small compared
+// to ttl so that the lock is still valid once a majority granted it, but
+// long enough for a round trip.
+func nodeTimeout(ttl time.Duration) time.Duration {
+	return max(ttl/10, 10*time.Millisecond)
+}
+
+// drift is the clock drift allowed for between the nodes over ttl, as
+// suggested by the Redlock algorithm.
+func drift(ttl time.Duration) time.Duration {
+	return ttl/100 + 2*time.Millisecond
+}
+
+func sleep(ctx context.Context, dur time.Duration) error {
+	t := time.NewTimer(dur)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Lock is an obtained lock.
+type Lock struct {
+	nodes nodes
+	key   string
+	token string
+	fence int64
+
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+func newLock(ns nodes, key, token string) *Lock {
+	return &Lock{
+		nodes: ns,
+		key:   key,
+		token: token,
+		done:  make(chan struct{}),
+	}
+}
+
+func (l *Lock) Key() string {
+	return l.key
+}
+
+// Token is the random value identifying this holder.
+func (l *Lock) Token() string {
+	return l.token
+}
+
+// Fence is the fencing token of this acquisition, greater than the one of
+// any previous acquisition of the key. With several nodes it is the
+// highest of their counters.
+func (l *Lock) Fence() int64 {
+	return l.fence
+}
+
+// Done is closed once the lock is released, or when the lease is found
+// lost while auto-refreshed.
+func (l *Lock) Done() <-chan struct{} {
+	return l.done
+}
+
+func (l *Lock) close() {
+	l.doneOnce.Do(func() {
+		close(l.done)
+	})
+}
+
+// TTL returns the remaining lease, held by a majority of the nodes, or
+// ErrNotHeld.
+func (l *Lock) TTL(ctx context.Context) (time.Duration, error) {
+	var (
+		mu  sync.Mutex
+		ttl []time.Duration
+	)
+	ok, notOK, err := l.nodes.each(ctx, func(ctx context.Context, _ int, rdb go_redis.Cmdable) (bool, error) {
+		ms, err := ttlScript.Run(ctx, rdb, []string{l.key}, l.token).Int64()
+		if err != nil || ms < 0 {
+			// -3: not held, -2: expired meanwhile
+			return false, err
+		}
+		mu.Lock()
+		ttl = append(ttl, time.Duration(ms)*time.Millisecond)
+		mu.Unlock()
+		return true, nil
+	})
+	if err := l.nodes.verdict(ok, notOK, err); err != nil {
+		return 0, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	slices.Sort(ttl)
+	return ttl[len(ttl)-l.nodes.quorum()], nil
+}
+
+// Refresh extends the lease to ttl, or returns ErrNotHeld.
+func (l *Lock) Refresh(ctx context.Context, ttl time.Duration) error {
+	ok, notOK, err := l.nodes.each(ctx, func(ctx context.Context, _ int, rdb go_redis.Cmdable) (bool, error) {
+		return refreshScript.Run(ctx, rdb, []string{l.key}, l.token, ttl.Milliseconds()).Bool()
+	})
+	return l.nodes.verdict(ok, notOK, err)
+}
+
+// Release unlocks, or returns ErrNotHeld if the lease was lost.
+func (l *Lock) Release(ctx context.Context) error {
+	l.close()
+	ok, notOK, err := l.nodes.each(ctx, func(ctx context.Context, _ int, rdb go_redis.Cmdable) (bool, error) {
+		return releaseScript.Run(ctx, rdb, []string{l.key}, l.token).Bool()
+	})
+	return l.nodes.verdict(ok, notOK, err)
+}
+
+// watch calls refresh every third of ttl until done is closed. Failed
+// refreshes are retried at the next tick, a lost lease calls lost.
+func watch(done <-chan struct{}, ttl time.Duration, refresh func(ctx context.Context, ttl time.Duration) error, lost func()) {
+	interval := ttl / 3
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		err := refresh(ctx, ttl)
+		cancel()
+		if errors.Is(err, ErrNotHeld) {
+			lost()
+			return
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"github.com/mingolm/go-redis/internal/redistest"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLocks runs the lock scripts on keys in memory, at the time now.
+type fakeLocks struct {
+	mu        sync.Mutex
+	now       int64 // ms
+	values    map[string]string
+	expiry    map[string]int64
+	fences    map[string]int64
+	refreshes int
+	// replied to every command instead of its result, which is still
+	// applied
+	err string
+}
+
+func newFakeLocks() *fakeLocks {
+	return &fakeLocks{
+		now:    time.Now().UnixMilli(),
+		values: make(map[string]string),
+		expiry: make(map[string]int64),
+		fences: make(map[string]int64),
+	}
+}
+
+func (f *fakeLocks) handle(cn net.Conn, args []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// EVALSHA sha numkeys keys... args...
+	if args[0] != "EVALSHA" {
+		redistest.Error(cn, "ERR unknown command")
+		return
+	}
+	numKeys, _ := strconv.Atoi(args[2])
+	keys, argv := args[3:3+numKeys], args[3+numKeys:]
+	key := keys[0]
+	held := f.get(key) == argv[0]
+
+	var n int64
+	switch args[1] {
+	case obtainScript.Hash():
+		if f.get(key) == "" {
+			ttl, _ := strconv.ParseInt(argv[1], 10, 64)
+			f.values[key], f.expiry[key] = argv[0], f.now+ttl
+			f.fences[keys[1]]++
+			n = f.fences[keys[1]]
+		}
+	case refreshScript.Hash():
+		f.refreshes++
+		if held {
+			ttl, _ := strconv.ParseInt(argv[1], 10, 64)
+			f.expiry[key] = f.now + ttl
+			n = 1
+		}
+	case releaseScript.Hash():
+		if held {
+			delete(f.values, key)
+			n = 1
+		}
+	case ttlScript.Hash():
+		n = -3
+		if held {
+			n = f.expiry[key] - f.now
+		}
+	default:
+		redistest.Error(cn, "NOSCRIPT No matching script")
+		return
+	}
+	if f.err != "" {
+		redistest.Error(cn, f.err)
+		return
+	}
+	redistest.Int(cn, n)
+}
+
+// get returns the value of key, empty if missing or expired.
+func (f *fakeLocks) get(key string) string {
+	if f.expiry[key] <= f.now {
+		delete(f.values, key)
+	}
+	return f.values[key]
+}
+
+func (f *fakeLocks) set(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[key], f.expiry[key] = value, f.now+time.Hour.Milliseconds()
+}
+
+func (f *fakeLocks) holder(key string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.get(key)
+}
+
+func (f *fakeLocks) advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now += d.Milliseconds()
+}
+
+func TestLock(t *testing.T) {
+	f := newFakeLocks()
+	c := New(redistest.NewClient(f.handle))
+	ctx := context.Background()
+
+	l, err := c.Obtain(ctx, "job", time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Key() != "job" || f.holder("job") != l.Token() || l.Fence() != 1 {
+		t.Fatalf("got key %s, token %s, fence %d", l.Key(), l.Token(), l.Fence())
+	}
+	if _, err := c.Obtain(ctx, "job", time.Second, nil); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("got %v, want ErrNotObtained", err)
+	}
+
+	f.advance(600 * time.Millisecond)
+	if ttl, err := l.TTL(ctx); err != nil || ttl != 400*time.Millisecond {
+		t.Fatalf("got %v, %v", ttl, err)
+	}
+	if err := l.Refresh(ctx, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	f.advance(600 * time.Millisecond)
+	// refreshed, still held
+	if ttl, err := l.TTL(ctx); err != nil || ttl != 400*time.Millisecond {
+		t.Fatalf("got %v, %v", ttl, err)
+	}
+
+	if err := l.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-l.Done():
+	default:
+		t.Fatal("done not closed on release")
+	}
+	if f.holder("job") != "" {
+		t.Fatal("lock not deleted")
+	}
+	if err := l.Release(ctx); !errors.Is(err, ErrNotHeld) {
+		t.Fatalf("got %v, want ErrNotHeld", err)
+	}
+
+	// the fence keeps increasing, and an expired lease can't be touched
+	l, err = c.Obtain(ctx, "job", time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Fence() != 2 {
+		t.Fatalf("got fence %d, want 2", l.Fence())
+	}
+	f.advance(time.Second)
+	if _, err := l.TTL(ctx); !errors.Is(err, ErrNotHeld) {
+		t.Fatalf("got %v, want ErrNotHeld", err)
+	}
+	if err := l.Refresh(ctx, time.Second); !errors.Is(err, ErrNotHeld) {
+		t.Fatalf("got %v, want ErrNotHeld", err)
+	}
+}
+
+func TestLockCompare(t *testing.T) {
+	f := newFakeLocks()
+	c := New(redistest.NewClient(f.handle))
+	ctx := context.Background()
+
+	l, err := c.Obtain(ctx, "job", time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the lease expired and someone else took the lock
+	f.set("job", "other")
+
+	if err := l.Refresh(ctx, time.Second); !errors.Is(err, ErrNotHeld) {
+		t.Fatalf("got %v, want ErrNotHeld", err)
+	}
+	if err := l.Release(ctx); !errors.Is(err, ErrNotHeld) {
+		t.Fatalf("got %v, want ErrNotHeld", err)
+	}
+	if f.holder("job") != "other" {
+		t.Fatal("lock of another holder deleted")
+	}
+}
+
+func TestLockObtainRetry(t *testing.T) {
+	f := newFakeLocks()
+	c := New(redistest.NewClient(f.handle))
+	ctx := context.Background()
+	opt := &Options{RetryStrategy: LimitRetry(LinearBackoff(10*time.Millisecond), 3)}
+
+	f.set("job", "other")
+	start := time.Now()
+	if _, err := c.Obtain(ctx, "job", time.Second, opt); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("got %v, want ErrNotObtained", err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Fatalf("gave up after %v, want 3 retries", elapsed)
+	}
+
+	// errors are returned as they are, without retrying
+	f.mu.Lock()
+	f.err = "ERR boom"
+	f.mu.Unlock()
+	start = time.Now()
+	_, err := c.Obtain(ctx, "job2", time.Second, opt)
+	if err == nil || errors.Is(err, ErrNotObtained) || err.Error() != "ERR boom" {
+		t.Fatalf("got %v, want ERR boom", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 10*time.Millisecond {
+		t.Fatalf("retried for %v", elapsed)
+	}
+}
+
+func TestLockWatch(t *testing.T) {
+	f := newFakeLocks()
+	c := New(redistest.NewClient(f.handle))
+	ctx := context.Background()
+
+	l, err := c.Obtain(ctx, "job", 60*time.Millisecond, &Options{AutoRefresh: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(70 * time.Millisecond)
+	f.mu.Lock()
+	refreshes := f.refreshes
+	f.mu.Unlock()
+	if refreshes < 2 {
+		t.Fatalf("got %d refreshes, want 2", refreshes)
+	}
+	select {
+	case <-l.Done():
+		t.Fatal("done closed while held")
+	default:
+	}
+
+	f.set("job", "other")
+	select {
+	case <-l.Done():
+	case <-time.After(time.Second):
+		t.Fatal("done not closed on loss")
+	}
+}
@@ -0,0 +1,78 @@
+package lock
+
+import (
+	"context"
+	go_redis "github.com/mingolm/go-redis"
+)
+
+// nodes are the independent servers a lock is held on; it is held while
+// a majority of them agree.
+type nodes []go_redis.Cmdable
+
+func (ns nodes) quorum() int {
+	return len(ns)/2 + 1
+}
+
+// each runs fn on every node, concurrently when several, and counts the
+// nodes it reported true and false for. Nodes not replying before ctx is
+// done count as failed.
+func (ns nodes) each(ctx context.Context, fn func(ctx context.Context, i int, rdb go_redis.Cmdable) (bool, error)) (ok, notOK int, err error) {
+	if len(ns) == 1 {
+		held, err := fn(ctx, 0, ns[0])
+		switch {
+		case err != nil:
+			return 0, 0, err
+		case held:
+			return 1, 0, nil
+		default:
+			return 0, 1, nil
+		}
+	}
+
+	type result struct {
+		held bool
+		err  error
+	}
+	results := make(chan result, len(ns))
+	for i, rdb := range ns {
+		go func() {
+			held, err := fn(ctx, i, rdb)
+			results <- result{held, err}
+		}()
+	}
+
+	for range ns {
+		select {
+		case r := <-results:
+			switch {
+			case r.err != nil:
+				if err == nil {
+					err = r.err
+				}
+			case r.held:
+				ok++
+			default:
+				notOK++
+			}
+		case <-ctx.Done():
+			if err == nil {
+				err = ctx.Err()
+			}
+			return ok, notOK, err
+		}
+	}
+	return ok, notOK, err
+}
+
+// verdict is nil if a quorum of nodes succeeded, ErrNotHeld if enough of
+// them refused for a quorum to be out of reach, and the first error
+// otherwise.
+func (ns nodes) verdict(ok, notOK int, err error) error {
+	if ok >= ns.quorum() {
+		return nil
+	}
+	if err != nil && notOK <= len(ns)-ns.quorum() {
+		return err
+	}
+	return ErrNotHeld
+}
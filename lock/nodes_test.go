@@ -0,0 +1,61 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	go_redis "github.com/mingolm/go-redis"
+	"testing"
+	"time"
+)
+
+func TestNodesVerdict(t *testing.T) {
+	errDown := errors.New("down")
+	ns := make(nodes, 5)
+
+	for _, tt := range []struct {
+		replies []error // nil: granted, ErrNotHeld: refused
+		want    error
+	}{
+		{[]error{nil, nil, nil, ErrNotHeld, ErrNotHeld}, nil},
+		{[]error{nil, nil, errDown, errDown, ErrNotHeld}, errDown},
+		{[]error{nil, nil, ErrNotHeld, ErrNotHeld, ErrNotHeld}, ErrNotHeld},
+		// the failed node could have made the majority
+		{[]error{nil, nil, ErrNotHeld, ErrNotHeld, errDown}, errDown},
+		{[]error{nil, ErrNotHeld, ErrNotHeld, ErrNotHeld, errDown}, ErrNotHeld},
+		{[]error{nil, nil, nil, errDown, errDown}, nil},
+	} {
+		ok, notOK, err := ns.each(context.Background(), func(ctx context.Context, i int, _ go_redis.Cmdable) (bool, error) {
+			switch err := tt.replies[i]; err {
+			case nil:
+				return true, nil
+			case ErrNotHeld:
+				return false, nil
+			default:
+				return false, err
+			}
+		})
+		if got := ns.verdict(ok, notOK, err); got != tt.want {
+			t.Errorf("%v: got %v, want %v", tt.replies, got, tt.want)
+		}
+	}
+}
+
+func TestNodesEachTimeout(t *testing.T) {
+	ns := make(nodes, 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// a hung node doesn't block once a majority replied and ctx is done
+	ok, notOK, err := ns.each(ctx, func(ctx context.Context, i int, _ go_redis.Cmdable) (bool, error) {
+		if i == 2 {
+			time.Sleep(time.Second)
+		}
+		return true, nil
+	})
+	if ok != 2 || notOK != 0 || err != context.DeadlineExceeded {
+		t.Fatalf("got %d, %d, %v", ok, notOK, err)
+	}
+	if ns.verdict(ok, notOK, err) != nil {
+		t.Fatal("majority not granted")
+	}
+}
@@ -0,0 +1,19 @@
+package lock
+
+type Options struct {
+	// RetryStrategy retries while the lock (or not enough permits of a
+	// Semaphore) is held by someone else, within the deadline of the
+	// context passed to Obtain or Acquire.
+	// Default is NoRetry.
+	RetryStrategy RetryStrategy
+
+	// AutoRefresh extends the lease every third of its TTL until
+	// Release. Done is closed if the lease is lost meanwhile.
+	AutoRefresh bool
+}
+
+func (opt *Options) init() {
+	if opt.RetryStrategy == nil {
+		opt.RetryStrategy = NoRetry()
+	}
+}
@@ -0,0 +1,21 @@
+package lock
+
+import go_redis "github.com/mingolm/go-redis"
+
+// NewRedlock returns a client locking with the Redlock algorithm on
+// independent servers (not replicas of each other): a lock is obtained
+// when a majority of them grant it before its lease, minus an allowance
+// for clock drift, runs out. An attempt waits a tenth of the lease for the
+// nodes, and failing to reach a majority is undone on every node.
+//
+// The fencing token is the highest of the counters of the granting nodes.
+// Any two majorities share a node, so tokens grow across acquisitions, but
+// not strictly: a token may repeat when the majorities differ.
+func NewRedlock(rdbs ...go_redis.Cmdable) *Client {
+	if len(rdbs) == 0 {
+		panic("lock: no redis client")
+	}
+	ns := make(nodes, len(rdbs))
+	copy(ns, rdbs)
+	return &Client{nodes: ns}
+}
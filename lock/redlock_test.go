@@ -0,0 +1,121 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	go_redis "github.com/mingolm/go-redis"
+	"github.com/mingolm/go-redis/internal/redistest"
+	"net"
+	"testing"
+	"time"
+)
+
+func newRedlock(fs ...*fakeLocks) *Client {
+	rdbs := make([]go_redis.Cmdable, len(fs))
+	for i, f := range fs {
+		rdbs[i] = redistest.NewClient(f.handle)
+	}
+	return NewRedlock(rdbs...)
+}
+
+func TestRedlockMajority(t *testing.T) {
+	fs := []*fakeLocks{newFakeLocks(), newFakeLocks(), newFakeLocks()}
+	c := newRedlock(fs...)
+	ctx := context.Background()
+
+	fs[2].set("job", "other")
+	fs[1].mu.Lock()
+	fs[1].fences["job:fence"] = 7
+	fs[1].mu.Unlock()
+
+	l, err := c.Obtain(ctx, "job", time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs[0].holder("job") != l.Token() || fs[1].holder("job") != l.Token() {
+		t.Fatal("lock not set on the majority")
+	}
+	if l.Fence() != 8 {
+		t.Fatalf("got fence %d, want the highest, 8", l.Fence())
+	}
+	if err := l.Refresh(ctx, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if fs[0].holder("job") != "" || fs[1].holder("job") != "" || fs[2].holder("job") != "other" {
+		t.Fatal("release didn't delete only the own locks")
+	}
+}
+
+func TestRedlockMinority(t *testing.T) {
+	fs := []*fakeLocks{newFakeLocks(), newFakeLocks(), newFakeLocks()}
+	c := newRedlock(fs...)
+	ctx := context.Background()
+
+	// the last node sets the lock but its reply is lost
+	fs[0].set("job", "other")
+	fs[1].set("job", "other")
+	fs[2].mu.Lock()
+	fs[2].err = "ERR connection lost"
+	fs[2].mu.Unlock()
+
+	if _, err := c.Obtain(ctx, "job", time.Second, nil); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("got %v, want ErrNotObtained", err)
+	}
+	if fs[2].holder("job") != "" {
+		t.Fatal("lock of the failed node not released")
+	}
+
+	// a granted minority is released
+	fs = []*fakeLocks{newFakeLocks(), newFakeLocks(), newFakeLocks()}
+	c = newRedlock(fs...)
+	fs[0].set("job", "other")
+	fs[2].set("job", "other")
+	if _, err := c.Obtain(ctx, "job", time.Second, nil); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("got %v, want ErrNotObtained", err)
+	}
+	if fs[1].holder("job") != "" {
+		t.Fatal("lock of the minority not released")
+	}
+}
+
+func TestRedlockDrift(t *testing.T) {
+	fs := []*fakeLocks{newFakeLocks(), newFakeLocks(), newFakeLocks()}
+	c := newRedlock(fs...)
+
+	// the drift allowed for exceeds the lease: granted, but invalid
+	// before being returned
+	if _, err := c.Obtain(context.Background(), "job", time.Millisecond, nil); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("got %v, want ErrNotObtained", err)
+	}
+	for i, f := range fs {
+		if f.holder("job") != "" {
+			t.Fatalf("lock of node %d not released", i)
+		}
+	}
+}
+
+func TestRedlockHungNode(t *testing.T) {
+	fs := []*fakeLocks{newFakeLocks(), newFakeLocks()}
+	hung := make(chan struct{})
+	t.Cleanup(func() { close(hung) })
+	c := NewRedlock(
+		redistest.NewClient(fs[0].handle),
+		redistest.NewClient(fs[1].handle),
+		redistest.NewClient(func(cn net.Conn, args []string) { <-hung }),
+	)
+
+	start := time.Now()
+	l, err := c.Obtain(context.Background(), "job", time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("obtained after %v", elapsed)
+	}
+	if fs[0].holder("job") != l.Token() || fs[1].holder("job") != l.Token() {
+		t.Fatal("lock not set on the majority")
+	}
+}
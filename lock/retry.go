@@ -0,0 +1,45 @@
+package lock
+
+import "time"
+
+// RetryStrategy returns the delay before the given retry, counted from 1,
+// or 0 to give up.
+type RetryStrategy func(attempt int) time.Duration
+
+// NoRetry gives up after the first attempt.
+func NoRetry() RetryStrategy {
+	return func(int) time.Duration {
+		return 0
+	}
+}
+
+// LinearBackoff retries every d.
+func LinearBackoff(d time.Duration) RetryStrategy {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff retries after min, doubling the delay up to max.
+func ExponentialBackoff(min, max time.Duration) RetryStrategy {
+	return func(attempt int) time.Duration {
+		d := min
+		for i := 1; i < attempt && d < max; i++ {
+			d <<= 1
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// LimitRetry gives up after n retries of s.
+func LimitRetry(s RetryStrategy, n int) RetryStrategy {
+	return func(attempt int) time.Duration {
+		if attempt > n {
+			return 0
+		}
+		return s(attempt)
+	}
+}
@@ -0,0 +1,33 @@
+package lock
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRetryStrategy(t *testing.T) {
+	delays := func(s RetryStrategy, n int) []time.Duration {
+		var ds []time.Duration
+		for attempt := 1; attempt <= n; attempt++ {
+			ds = append(ds, s(attempt))
+		}
+		return ds
+	}
+
+	for _, tt := range []struct {
+		name string
+		s    RetryStrategy
+		want []time.Duration
+	}{
+		{"no retry", NoRetry(), []time.Duration{0, 0}},
+		{"linear", LinearBackoff(time.Second), []time.Duration{time.Second, time.Second}},
+		{"exponential", ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond),
+			[]time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond}},
+		{"limit", LimitRetry(LinearBackoff(time.Second), 2), []time.Duration{time.Second, time.Second, 0}},
+	} {
+		if got := delays(tt.s, len(tt.want)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	go_redis "github.com/mingolm/go-redis"
+	"sync"
+	"time"
+)
+
+// acquireScript drops the expired leases and adds n members scored by
+// their expiry if enough permits are left. The key expires with the last
+// lease.
+var acquireScript = go_redis.NewScript(`
+redis.replicate_commands()
+
+local permits = tonumber(ARGV[1])
+local n = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+
+local t = redis.call("TIME")
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now)
+if redis.call("ZCARD", KEYS[1]) + n > permits then
+	return 0
+end
+for i = 1, n do
+	redis.call("ZADD", KEYS[1], now + ttl, ARGV[4] .. ":" .. i)
+end
+local last = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+redis.call("PEXPIREAT", KEYS[1], last[2])
+return 1
+`)
+
+// extendScript extends the members of the lease still held, and returns
+// how many.
+var extendScript = go_redis.NewScript(`
+redis.replicate_commands()
+
+local n = tonumber(ARGV[1])
+local ttl = tonumber(ARGV[2])
+
+local t = redis.call("TIME")
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+local held = 0
+for i = 1, n do
+	local member = ARGV[3] .. ":" .. i
+	local expiry = redis.call("ZSCORE", KEYS[1], member)
+	if expiry and tonumber(expiry) > now then
+		redis.call("ZADD", KEYS[1], now + ttl, member)
+		held = held + 1
+	end
+end
+if held > 0 then
+	local last = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+	redis.call("PEXPIREAT", KEYS[1], last[2])
+end
+return held
+`)
+
+// releaseLeaseScript removes the members of the lease, and returns how many
+// were still held, not expired.
+var releaseLeaseScript = go_redis.NewScript(`
+redis.replicate_commands()
+
+local n = tonumber(ARGV[1])
+
+local t = redis.call("TIME")
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+local held = 0
+for i = 1, n do
+	local member = ARGV[2] .. ":" .. i
+	local expiry = redis.call("ZSCORE", KEYS[1], member)
+	if expiry then
+		redis.call("ZREM", KEYS[1], member)
+		if tonumber(expiry) > now then
+			held = held + 1
+		end
+	end
+end
+return held
+`)
+
+// Semaphore limits the holders of a resource to a number of permits. Each
+// acquisition is a lease expiring unless refreshed, so the permits of a
+// crashed holder come back:
+//
+//	sem := lock.NewSemaphore(redis, "exports", 4)
+//	lease, err := sem.Acquire(ctx, 1, time.Minute, &lock.Options{
+//		RetryStrategy: lock.LinearBackoff(time.Second),
+//	})
+//	if err != nil {
+//	}
+//	defer lease.Release(ctx)
+type Semaphore struct {
+	rdb     go_redis.Cmdable
+	key     string
+	permits int
+}
+
+func NewSemaphore(rdb go_redis.Cmdable, key string, permits int) *Semaphore {
+	return &Semaphore{
+		rdb:     rdb,
+		key:     key,
+		permits: permits,
+	}
+}
+
+// Acquire takes n permits for ttl, retrying according to opt.RetryStrategy
+// while fewer are available. It returns ErrNotObtained when giving up or
+// when ctx is done.
+func (s *Semaphore) Acquire(ctx context.Context, n int, ttl time.Duration, opt *Options) (*Lease, error) {
+	if n <= 0 || n > s.permits {
+		return nil, fmt.Errorf("lock: can't acquire %d of %d permits", n, s.permits)
+	}
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := retry(ctx, opt.RetryStrategy, func() error {
+		ok, err := acquireScript.Run(ctx, s.rdb, []string{s.key}, s.permits, n, ttl.Milliseconds(), token).Bool()
+		if err == nil && !ok {
+			err = ErrNotHeld
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	l := &Lease{
+		sem:   s,
+		n:     n,
+		token: token,
+		done:  make(chan struct{}),
+	}
+	if opt.AutoRefresh {
+		go watch(l.done, ttl, l.Refresh, l.close)
+	}
+	return l, nil
+}
+
+// Lease holds permits of a Semaphore.
+type Lease struct {
+	sem   *Semaphore
+	n     int
+	token string
+
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+// N is the number of permits held.
+func (l *Lease) N() int {
+	return l.n
+}
+
+// Done is closed once the lease is released, or when it is found lost
+// while auto-refreshed.
+func (l *Lease) Done() <-chan struct{} {
+	return l.done
+}
+
+func (l *Lease) close() {
+	l.doneOnce.Do(func() {
+		close(l.done)
+	})
+}
+
+// Refresh extends the lease to ttl, or returns ErrNotHeld if any permit
+// expired.
+func (l *Lease) Refresh(ctx context.Context, ttl time.Duration) error {
+	held, err := extendScript.Run(ctx, l.sem.rdb, []string{l.sem.key}, l.n, ttl.Milliseconds(), l.token).Int64()
+	if err != nil {
+		return err
+	}
+	if held < int64(l.n) {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+// Release gives the permits back, or returns ErrNotHeld if they expired.
+func (l *Lease) Release(ctx context.Context) error {
+	l.close()
+	held, err := releaseLeaseScript.Run(ctx, l.sem.rdb, []string{l.sem.key}, l.n, l.token).Int64()
+	if err != nil {
+		return err
+	}
+	if held < int64(l.n) {
+		return ErrNotHeld
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mingolm/go-redis/internal/redistest"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSemaphoreAcquireInvalid(t *testing.T) {
+	sem := NewSemaphore(nil, "sem", 3)
+	for _, n := range []int{0, -1, 4} {
+		if _, err := sem.Acquire(context.Background(), n, time.Second, nil); err == nil {
+			t.Errorf("acquire %d of 3: want error", n)
+		}
+	}
+}
+
+// fakeSemaphore runs the semaphore scripts on a sorted set in memory, at
+// the time now.
+type fakeSemaphore struct {
+	mu      sync.Mutex
+	now     int64 // ms
+	members map[string]int64
+}
+
+func (f *fakeSemaphore) handle(cn net.Conn, args []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// EVALSHA sha 1 key args...
+	if args[0] != "EVALSHA" {
+		redistest.Error(cn, "ERR unknown command")
+		return
+	}
+	argv := args[4:]
+	arg := func(i int) int64 {
+		n, _ := strconv.ParseInt(argv[i], 10, 64)
+		return n
+	}
+	switch args[1] {
+	case acquireScript.Hash():
+		permits, n, ttl, token := arg(0), arg(1), arg(2), argv[3]
+		for m, expiry := range f.members {
+			if expiry <= f.now {
+				delete(f.members, m)
+			}
+		}
+		if int64(len(f.members))+n > permits {
+			redistest.Int(cn, 0)
+			return
+		}
+		for i := int64(1); i <= n; i++ {
+			f.members[fmt.Sprintf("%s:%d", token, i)] = f.now + ttl
+		}
+		redistest.Int(cn, 1)
+	case extendScript.Hash():
+		n, ttl, token := arg(0), arg(1), argv[2]
+		var held int64
+		for i := int64(1); i <= n; i++ {
+			m := fmt.Sprintf("%s:%d", token, i)
+			if expiry, ok := f.members[m]; ok && expiry > f.now {
+				f.members[m] = f.now + ttl
+				held++
+			}
+		}
+		redistest.Int(cn, held)
+	case releaseLeaseScript.Hash():
+		n, token := arg(0), argv[1]
+		var held int64
+		for i := int64(1); i <= n; i++ {
+			m := fmt.Sprintf("%s:%d", token, i)
+			if expiry, ok := f.members[m]; ok {
+				delete(f.members, m)
+				if expiry > f.now {
+					held++
+				}
+			}
+		}
+		redistest.Int(cn, held)
+	default:
+		redistest.Error(cn, "NOSCRIPT No matching script")
+	}
+}
+
+func (f *fakeSemaphore) advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now += d.Milliseconds()
+}
+
+func TestSemaphore(t *testing.T) {
+	f := &fakeSemaphore{now: time.Now().UnixMilli(), members: make(map[string]int64)}
+	sem := NewSemaphore(redistest.NewClient(f.handle), "sem", 3)
+	ctx := context.Background()
+
+	lease, err := sem.Acquire(ctx, 2, time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease.N() != 2 {
+		t.Fatalf("got %d permits", lease.N())
+	}
+	if _, err := sem.Acquire(ctx, 2, time.Second, nil); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("got %v, want ErrNotObtained", err)
+	}
+
+	f.advance(600 * time.Millisecond)
+	if err := lease.Refresh(ctx, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	f.advance(600 * time.Millisecond)
+	// refreshed, still held
+	other, err := sem.Acquire(ctx, 1, time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lease.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-lease.Done():
+	default:
+		t.Fatal("done not closed on release")
+	}
+
+	// expired but not purged yet
+	f.advance(2 * time.Second)
+	if err := other.Refresh(ctx, time.Second); !errors.Is(err, ErrNotHeld) {
+		t.Fatalf("got %v, want ErrNotHeld", err)
+	}
+	if err := other.Release(ctx); !errors.Is(err, ErrNotHeld) {
+		t.Fatalf("got %v, want ErrNotHeld", err)
+	}
+	if _, err := sem.Acquire(ctx, 3, time.Second, nil); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,215 @@
+package nearcache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// itemOverhead approximates the memory of an item besides its strings.
+const itemOverhead = 128
+
+type item struct {
+	key    string
+	field  string
+	hash   bool
+	val    string
+	expiry time.Time
+	size   int64
+}
+
+// keyItems indexes the items of a key: its string value, or hash fields.
+type keyItems struct {
+	value  *list.Element
+	fields map[string]*list.Element
+}
+
+// reads tracks the reads from Redis of a key in progress, and the
+// invalidations of the key since they started.
+type reads struct {
+	n       int
+	version uint64
+}
+
+// ticket is taken before reading a value from Redis, and passed to add,
+// which drops the value if the key was invalidated meanwhile.
+type ticket struct {
+	epoch   uint64
+	version uint64
+}
+
+// lru is a least recently used cache bounded in bytes, with expiring items.
+type lru struct {
+	mu        sync.Mutex
+	maxBytes  int64
+	bytes     int64
+	ll        *list.List
+	keys      map[string]*keyItems
+	reads     map[string]*reads
+	epoch     uint64 // 每次清空递增
+	evictions int64
+}
+
+func newLRU(maxBytes int64) *lru {
+	return &lru{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		keys:     make(map[string]*keyItems),
+		reads:    make(map[string]*reads),
+	}
+}
+
+func (l *lru) lookup(key, field string, hash bool) *list.Element {
+	ki := l.keys[key]
+	if ki == nil {
+		return nil
+	}
+	if hash {
+		return ki.fields[field]
+	}
+	return ki.value
+}
+
+func (l *lru) get(key, field string, hash bool, now time.Time) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := l.lookup(key, field, hash)
+	if e == nil {
+		return "", false
+	}
+	it := e.Value.(*item)
+	if now.After(it.expiry) {
+		l.removeElement(e)
+		return "", false
+	}
+	l.ll.MoveToFront(e)
+	return it.val, true
+}
+
+// begin starts a read of key from Redis, which must be ended by end.
+func (l *lru) begin(key string) ticket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r := l.reads[key]
+	if r == nil {
+		r = &reads{}
+		l.reads[key] = r
+	}
+	r.n++
+	return ticket{epoch: l.epoch, version: r.version}
+}
+
+func (l *lru) end(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if r := l.reads[key]; r != nil {
+		if r.n--; r.n == 0 {
+			delete(l.reads, key)
+		}
+	}
+}
+
+// add caches a value read with t, between begin and end.
+func (l *lru) add(t ticket, key, field string, hash bool, val string, expiry time.Time) {
+	size := int64(len(key)+len(field)+len(val)) + itemOverhead
+	if size > l.maxBytes {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t.epoch != l.epoch {
+		return
+	}
+	if r := l.reads[key]; r != nil && r.version != t.version {
+		return
+	}
+
+	if e := l.lookup(key, field, hash); e != nil {
+		l.removeElement(e)
+	}
+	e := l.ll.PushFront(&item{
+		key:    key,
+		field:  field,
+		hash:   hash,
+		val:    val,
+		expiry: expiry,
+		size:   size,
+	})
+	ki := l.keys[key]
+	if ki == nil {
+		ki = &keyItems{}
+		l.keys[key] = ki
+	}
+	if hash {
+		if ki.fields == nil {
+			ki.fields = make(map[string]*list.Element)
+		}
+		ki.fields[field] = e
+	} else {
+		ki.value = e
+	}
+	l.bytes += size
+
+	for l.bytes > l.maxBytes {
+		l.removeElement(l.ll.Back())
+		l.evictions++
+	}
+}
+
+// remove drops every item of keys.
+func (l *lru) remove(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if r := l.reads[key]; r != nil {
+			r.version++
+		}
+		ki := l.keys[key]
+		if ki == nil {
+			continue
+		}
+		if ki.value != nil {
+			l.removeElement(ki.value)
+		}
+		for _, e := range ki.fields {
+			l.removeElement(e)
+		}
+	}
+}
+
+// clear drops every item, and the values being read.
+func (l *lru) clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.epoch++
+	l.ll.Init()
+	clear(l.keys)
+	l.bytes = 0
+}
+
+func (l *lru) removeElement(e *list.Element) {
+	it := l.ll.Remove(e).(*item)
+	l.bytes -= it.size
+
+	ki := l.keys[it.key]
+	if it.hash {
+		delete(ki.fields, it.field)
+	} else {
+		ki.value = nil
+	}
+	if ki.value == nil && len(ki.fields) == 0 {
+		delete(l.keys, it.key)
+	}
+}
+
+func (l *lru) stats() (entries int, bytes, evictions int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ll.Len(), l.bytes, l.evictions
+}
@@ -0,0 +1,96 @@
+package nearcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	now := time.Now()
+	expiry := now.Add(time.Minute)
+	l := newLRU(3 * (itemOverhead + 3))
+
+	for i := 0; i < 3; i++ {
+		fill(l, fmt.Sprint("k", i), "", false, "v", expiry)
+	}
+	// k0 becomes the most recently used, k1 is evicted
+	if _, ok := l.get("k0", "", false, now); !ok {
+		t.Fatal("k0 missing")
+	}
+	fill(l, "k3", "", false, "v", expiry)
+	if _, ok := l.get("k1", "", false, now); ok {
+		t.Fatal("k1 not evicted")
+	}
+	if entries, bytes, evictions := l.stats(); entries != 3 || bytes != 3*(itemOverhead+3) || evictions != 1 {
+		t.Fatalf("got %d entries, %d bytes, %d evictions", entries, bytes, evictions)
+	}
+
+	// expired
+	if _, ok := l.get("k0", "", false, expiry.Add(time.Second)); ok {
+		t.Fatal("k0 not expired")
+	}
+}
+
+func TestLRURemove(t *testing.T) {
+	now := time.Now()
+	expiry := now.Add(time.Minute)
+	l := newLRU(1 << 20)
+
+	fill(l, "user:1", "", false, "v", expiry)
+	fill(l, "user:1", "name", true, "mingo", expiry)
+	fill(l, "user:1", "age", true, "18", expiry)
+	fill(l, "user:2", "name", true, "other", expiry)
+	if val, ok := l.get("user:1", "name", true, now); !ok || val != "mingo" {
+		t.Fatalf("got %q, %v", val, ok)
+	}
+
+	l.remove("user:1")
+	for _, field := range []string{"name", "age"} {
+		if _, ok := l.get("user:1", field, true, now); ok {
+			t.Fatalf("field %s not removed", field)
+		}
+	}
+	if _, ok := l.get("user:1", "", false, now); ok {
+		t.Fatal("value not removed")
+	}
+	if _, ok := l.get("user:2", "name", true, now); !ok {
+		t.Fatal("user:2 removed")
+	}
+	if entries, bytes, _ := l.stats(); entries != 1 || bytes != int64(len("user:2name"+"other")+itemOverhead) {
+		t.Fatalf("got %d entries, %d bytes", entries, bytes)
+	}
+
+	// a value read before an invalidation of its key is not cached, the
+	// reads of other keys are
+	t3, t4 := l.begin("user:3"), l.begin("user:4")
+	l.remove("user:3")
+	l.add(t3, "user:3", "", false, "stale", expiry)
+	l.add(t4, "user:4", "", false, "fresh", expiry)
+	l.end("user:3")
+	l.end("user:4")
+	if _, ok := l.get("user:3", "", false, now); ok {
+		t.Fatal("stale value cached")
+	}
+	if _, ok := l.get("user:4", "", false, now); !ok {
+		t.Fatal("value of another key dropped")
+	}
+	if len(l.reads) != 0 {
+		t.Fatalf("got %d reads left", len(l.reads))
+	}
+
+	// nor is a value read before the cache is cleared
+	t5 := l.begin("user:5")
+	l.clear()
+	l.add(t5, "user:5", "", false, "stale", expiry)
+	l.end("user:5")
+	if _, ok := l.get("user:5", "", false, now); ok {
+		t.Fatal("stale value cached after clear")
+	}
+}
+
+func fill(l *lru, key, field string, hash bool, val string, expiry time.Time) {
+	t := l.begin(key)
+	defer l.end(key)
+	l.add(t, key, field, hash, val, expiry)
+}
@@ -0,0 +1,234 @@
+// Package nearcache keeps recently read values in process memory, in
+// front of Redis. Writes through the cache invalidate the local copy and
+// are published to the other processes, which drop theirs. A process
+// losing its subscription drops all its values once subscribed again.
+//
+// Values are evicted least recently used first, within Options.MaxBytes.
+// There is no admission policy such as TinyLFU: reading many keys once,
+// e.g. in a scan, evicts the keys read often.
+package nearcache
+
+import (
+	"context"
+	"encoding/json"
+	go_redis "github.com/mingolm/go-redis"
+	"sync/atomic"
+	"time"
+)
+
+// Client is the part of *redis.Redis used by a Cache.
+type Client interface {
+	Get(ctx context.Context, key string) *go_redis.StringCmd
+	MGet(ctx context.Context, keys ...string) *go_redis.SliceCmd
+	HGet(ctx context.Context, key, field string) *go_redis.StringCmd
+	Set(ctx context.Context, key string, val any, expiration time.Duration) *go_redis.StatusCmd
+	HSet(ctx context.Context, key string, values ...interface{}) *go_redis.IntCmd
+	Del(ctx context.Context, keys ...string) *go_redis.IntCmd
+	Publish(ctx context.Context, channel string, message interface{}) *go_redis.IntCmd
+	// Subscribe is not called with Options.DisableInvalidation.
+	Subscribe(ctx context.Context, channels ...string) (*go_redis.PubSub, error)
+}
+
+type Stats struct {
+	Hits          int64
+	Misses        int64
+	Evictions     int64 // 因容量淘汰
+	Invalidations int64 // 收到的失效消息
+	Entries       int
+	Bytes         int64
+}
+
+// Cache serves Get, MGet and HGet from memory when possible. It is safe
+// for concurrent use.
+//
+//	nc, err := nearcache.New(ctx, redis, &nearcache.Options{TTL: 10 * time.Second})
+//	if err != nil {
+//	}
+//	defer nc.Close()
+//	val, err := nc.Get(ctx, "config:flags")
+type Cache struct {
+	rdb Client
+	opt *Options
+	lru *lru
+	ps  *go_redis.PubSub
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+}
+
+func New(ctx context.Context, rdb Client, opt *Options) (*Cache, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+
+	c := &Cache{
+		rdb: rdb,
+		opt: opt,
+		lru: newLRU(opt.MaxBytes),
+	}
+	if !opt.DisableInvalidation {
+		ps, err := rdb.Subscribe(ctx, opt.Channel)
+		if err != nil {
+			return nil, err
+		}
+		// the invalidations published while reconnecting are lost
+		ps.OnReconnect(c.lru.clear)
+		c.ps = ps
+		go c.listen()
+	}
+	return c, nil
+}
+
+func (c *Cache) listen() {
+	for msg := range c.ps.Channel() {
+		var keys []string
+		if err := json.Unmarshal([]byte(msg.Payload), &keys); err != nil {
+			continue
+		}
+		c.lru.remove(keys...)
+		c.invalidations.Add(1)
+	}
+}
+
+// Get returns proto.Nil for a missing key, which is not cached.
+func (c *Cache) Get(ctx context.Context, key string) (string, error) {
+	if val, ok := c.lru.get(key, "", false, time.Now()); ok {
+		c.hits.Add(1)
+		return val, nil
+	}
+	c.misses.Add(1)
+
+	t := c.lru.begin(key)
+	defer c.lru.end(key)
+	val, err := c.rdb.Get(ctx, key).Result()
+	if err != nil {
+		return "", err
+	}
+	c.lru.add(t, key, "", false, val, time.Now().Add(c.opt.TTL))
+	return val, nil
+}
+
+// MGet returns the values of keys, nil for the missing ones. Only the
+// keys not cached are read from Redis.
+func (c *Cache) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	vals := make([]interface{}, len(keys))
+	var missing []int
+	now := time.Now()
+	for i, key := range keys {
+		if val, ok := c.lru.get(key, "", false, now); ok {
+			vals[i] = val
+			continue
+		}
+		missing = append(missing, i)
+	}
+	c.hits.Add(int64(len(keys) - len(missing)))
+	c.misses.Add(int64(len(missing)))
+	if len(missing) == 0 {
+		return vals, nil
+	}
+
+	missingKeys := make([]string, len(missing))
+	tickets := make([]ticket, len(missing))
+	for i, idx := range missing {
+		missingKeys[i] = keys[idx]
+		tickets[i] = c.lru.begin(keys[idx])
+	}
+	defer func() {
+		for _, key := range missingKeys {
+			c.lru.end(key)
+		}
+	}()
+	fetched, err := c.rdb.MGet(ctx, missingKeys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	expiry := time.Now().Add(c.opt.TTL)
+	for i, val := range fetched {
+		vals[missing[i]] = val
+		if s, ok := val.(string); ok {
+			c.lru.add(tickets[i], missingKeys[i], "", false, s, expiry)
+		}
+	}
+	return vals, nil
+}
+
+// HGet returns proto.Nil for a missing field, which is not cached.
+func (c *Cache) HGet(ctx context.Context, key, field string) (string, error) {
+	if val, ok := c.lru.get(key, field, true, time.Now()); ok {
+		c.hits.Add(1)
+		return val, nil
+	}
+	c.misses.Add(1)
+
+	t := c.lru.begin(key)
+	defer c.lru.end(key)
+	val, err := c.rdb.HGet(ctx, key, field).Result()
+	if err != nil {
+		return "", err
+	}
+	c.lru.add(t, key, field, true, val, time.Now().Add(c.opt.TTL))
+	return val, nil
+}
+
+func (c *Cache) Set(ctx context.Context, key string, val any, expiration time.Duration) error {
+	err := c.rdb.Set(ctx, key, val, expiration).Err()
+	c.invalidate(ctx, key)
+	return err
+}
+
+func (c *Cache) HSet(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	n, err := c.rdb.HSet(ctx, key, values...).Result()
+	c.invalidate(ctx, key)
+	return n, err
+}
+
+func (c *Cache) Del(ctx context.Context, keys ...string) (int64, error) {
+	n, err := c.rdb.Del(ctx, keys...).Result()
+	c.invalidate(ctx, keys...)
+	return n, err
+}
+
+// Invalidate drops keys here and in the other processes, for keys
+// changed by other means than this cache.
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) error {
+	c.lru.remove(keys...)
+	if c.ps == nil {
+		return nil
+	}
+	payload, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	return c.rdb.Publish(ctx, c.opt.Channel, payload).Err()
+}
+
+// invalidate runs after a write, failed or not since it may have been
+// applied anyway. A failed publish leaves the other processes with stale
+// values until Options.TTL.
+func (c *Cache) invalidate(ctx context.Context, keys ...string) {
+	_ = c.Invalidate(ctx, keys...)
+}
+
+func (c *Cache) Stats() Stats {
+	entries, bytes, evictions := c.lru.stats()
+	return Stats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Evictions:     evictions,
+		Invalidations: c.invalidations.Load(),
+		Entries:       entries,
+		Bytes:         bytes,
+	}
+}
+
+// Close stops receiving invalidations and drops the cached values.
+func (c *Cache) Close() error {
+	c.lru.clear()
+	if c.ps == nil {
+		return nil
+	}
+	return c.ps.Close()
+}
@@ -0,0 +1,121 @@
+package nearcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	go_redis "github.com/mingolm/go-redis"
+	"github.com/mingolm/go-redis/internal/redistest"
+	"github.com/mingolm/go-redis/proto"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// fakeClient records the keys read from Redis.
+type fakeClient struct {
+	Client
+	mu    sync.Mutex
+	reads []string
+}
+
+func (c *fakeClient) read(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reads = append(c.reads, keys...)
+}
+
+func (c *fakeClient) Get(ctx context.Context, key string) *go_redis.StringCmd {
+	c.read(key)
+	return c.Client.Get(ctx, key)
+}
+
+func (c *fakeClient) MGet(ctx context.Context, keys ...string) *go_redis.SliceCmd {
+	c.read(keys...)
+	return c.Client.MGet(ctx, keys...)
+}
+
+func (c *fakeClient) HGet(ctx context.Context, key, field string) *go_redis.StringCmd {
+	c.read(key + "." + field)
+	return c.Client.HGet(ctx, key, field)
+}
+
+// newFakeClient serves GET, MGET, HGET and SET from values, where hash
+// fields are keyed "key.field".
+func newFakeClient(values map[string]string) *fakeClient {
+	var mu sync.Mutex
+	return &fakeClient{Client: redistest.NewClient(func(cn net.Conn, args []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		reply := func(key string) {
+			if v, ok := values[key]; ok {
+				redistest.Bulk(cn, v)
+			} else {
+				redistest.Nil(cn)
+			}
+		}
+		switch args[0] {
+		case "GET":
+			reply(args[1])
+		case "HGET":
+			reply(args[1] + "." + args[2])
+		case "MGET":
+			fmt.Fprintf(cn, "*%d\r\n", len(args)-1)
+			for _, key := range args[1:] {
+				reply(key)
+			}
+		case "SET":
+			values[args[1]] = args[2]
+			redistest.Status(cn, "OK")
+		default:
+			redistest.Error(cn, "ERR unknown command")
+		}
+	})}
+}
+
+func TestCache(t *testing.T) {
+	rdb := newFakeClient(map[string]string{"a": "1", "b": "2", "h.f": "3"})
+	ctx := context.Background()
+	nc, err := New(ctx, rdb, &Options{DisableInvalidation: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nc.Close()
+
+	for range 2 {
+		if val, err := nc.Get(ctx, "a"); err != nil || val != "1" {
+			t.Fatalf("got %q, %v", val, err)
+		}
+		if val, err := nc.HGet(ctx, "h", "f"); err != nil || val != "3" {
+			t.Fatalf("got %q, %v", val, err)
+		}
+	}
+	// missing keys are not cached
+	for range 2 {
+		if _, err := nc.Get(ctx, "x"); !errors.Is(err, proto.Nil) {
+			t.Fatalf("got %v, want proto.Nil", err)
+		}
+	}
+	vals, err := nc.MGet(ctx, "a", "b", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{"1", "2", nil}; !reflect.DeepEqual(vals, want) {
+		t.Fatalf("got %#v, want %#v", vals, want)
+	}
+
+	if err := nc.Set(ctx, "b", "4", 0); err != nil {
+		t.Fatal(err)
+	}
+	if val, err := nc.Get(ctx, "b"); err != nil || val != "4" {
+		t.Fatalf("got %q, %v after set", val, err)
+	}
+
+	if want := []string{"a", "h.f", "x", "x", "b", "x", "b"}; !reflect.DeepEqual(rdb.reads, want) {
+		t.Fatalf("read %q from Redis, want %q", rdb.reads, want)
+	}
+	if st := nc.Stats(); st.Hits != 3 || st.Misses != 7 || st.Entries != 3 {
+		t.Fatalf("got %+v", st)
+	}
+}
@@ -0,0 +1,40 @@
+package nearcache
+
+import "time"
+
+type Options struct {
+	// Maximum size of the cached keys and values, in bytes.
+	// Default is 64 MB.
+	MaxBytes int64
+
+	// TTL of the local copies. It bounds how long a value stays stale
+	// when changed without invalidation, e.g. by another client or by
+	// expiring in Redis.
+	// Default is 1 minute.
+	TTL time.Duration
+
+	// Pub/sub channel on which processes publish the keys they change.
+	// Default is "nearcache:invalidate".
+	Channel string
+	// Disable cross-process invalidation, e.g. for keys only written by
+	// this process.
+	DisableInvalidation bool
+}
+
+func (opt *Options) init() {
+	var (
+		maxBytes = int64(64 << 20)
+		ttl      = time.Minute
+		channel  = "nearcache:invalidate"
+	)
+
+	if opt.MaxBytes == 0 {
+		opt.MaxBytes = maxBytes
+	}
+	if opt.TTL == 0 {
+		opt.TTL = ttl
+	}
+	if opt.Channel == "" {
+		opt.Channel = channel
+	}
+}
@@ -33,6 +33,15 @@ type Options struct {
 	// Database to be selected after connecting to the server.
 	DB int
 
+	// Protocol 2 or 3. Use the version to negotiate RESP version with redis-server.
+	// Default is 3, falling back to 2 on servers that reject HELLO (Redis < 6.0).
+	Protocol int
+
+	// ClientName will execute the `CLIENT SETNAME ClientName` command for each conn.
+	ClientName string
+	// Disable sending `CLIENT SETINFO lib-name/lib-ver` on connect.
+	DisableIdentity bool
+
 	// Maximum number of retries before giving up.
 	// Default is 3 retries; -1 (not 0) disables retries.
 	MaxRetries int
@@ -67,6 +76,10 @@ type Options struct {
 
 	// TLS Config to use. When set TLS will be negotiated.
 	TLSConfig *tls.Config
+
+	// OnAttributes receives the RESP3 attributes sent along with the reply
+	// of cmd. Attributes are discarded when nil.
+	OnAttributes func(ctx context.Context, cmd Cmder, attrs map[interface{}]interface{})
 	// zap logger
 	Logger *zap.SugaredLogger
 }
@@ -84,6 +97,7 @@ func (opt *Options) init() {
 		connMaxIdleTime = time.Minute * 30
 		connMaxLifeTime = time.Hour
 		maxRetries      = 3
+		protocol        = 3
 		logger          = zap.S()
 	)
 
@@ -120,6 +134,9 @@ func (opt *Options) init() {
 	if opt.MaxRetries == 0 {
 		opt.MaxRetries = maxRetries
 	}
+	if opt.Protocol != 2 && opt.Protocol != 3 {
+		opt.Protocol = protocol
+	}
 	if opt.Logger == nil {
 		opt.Logger = logger
 	}
@@ -136,3 +153,10 @@ func (opt *Options) init() {
 		}
 	}
 }
+
+func (opt *Options) maxRetries() int {
+	if opt.MaxRetries < 0 {
+		return 0
+	}
+	return opt.MaxRetries
+}
@@ -0,0 +1,134 @@
+package go_redis
+
+import (
+	"bufio"
+	"context"
+	"github.com/mingolm/go-redis/pool"
+	"github.com/mingolm/go-redis/proto"
+)
+
+// Pipeline queues commands and sends them in a single round trip on Exec.
+// The commands hold their results once Exec returned:
+//
+//	pipe := redis.Pipeline()
+//	pipe.Set(ctx, "k1", "v1", time.Hour)
+//	get := pipe.Get(ctx, "k2")
+//	_, err := pipe.Exec(ctx)
+//	val, err := get.Result()
+//
+// Unlike single commands, pipelines are not retried. A Pipeline is not
+// safe for concurrent use.
+type Pipeline struct {
+	cmdable
+	r    *Redis
+	cmds []Cmder
+}
+
+func (r *Redis) Pipeline() *Pipeline {
+	p := &Pipeline{r: r}
+	p.cmdable = p.queue
+	return p
+}
+
+// Pipelined runs fn with a new Pipeline and executes it.
+func (r *Redis) Pipelined(ctx context.Context, fn func(p *Pipeline) error) ([]Cmder, error) {
+	p := r.Pipeline()
+	if err := fn(p); err != nil {
+		return nil, err
+	}
+	return p.Exec(ctx)
+}
+
+func (p *Pipeline) queue(ctx context.Context, cmd Cmder) error {
+	p.cmds = append(p.cmds, cmd)
+	return nil
+}
+
+// Len returns the number of queued commands.
+func (p *Pipeline) Len() int {
+	return len(p.cmds)
+}
+
+// Discard drops the queued commands.
+func (p *Pipeline) Discard() {
+	p.cmds = nil
+}
+
+// Exec sends the queued commands and reads their replies, setting the
+// result and error of each. It returns the commands and the first error
+// other than proto.Nil. The pipeline is empty afterwards.
+func (p *Pipeline) Exec(ctx context.Context) ([]Cmder, error) {
+	cmds := p.cmds
+	p.cmds = nil
+	if len(cmds) == 0 {
+		return nil, nil
+	}
+
+	var sent bool
+	err := p.r.connPool.WithConn(ctx, func(ctx context.Context, cn *pool.Conn) error {
+		sent = true
+		return p.r.processPipeline(ctx, cn, cmds)
+	})
+	if !sent {
+		setCmdsErr(cmds, err)
+	}
+	return cmds, err
+}
+
+type errSetter interface {
+	setErr(error)
+}
+
+func (c *baseCmd) setErr(err error) {
+	c.err = err
+}
+
+func setCmdErr(cmd Cmder, err error) {
+	if s, ok := cmd.(errSetter); ok {
+		s.setErr(err)
+	}
+}
+
+func setCmdsErr(cmds []Cmder, err error) {
+	for _, cmd := range cmds {
+		setCmdErr(cmd, err)
+	}
+}
+
+// processPipeline writes cmds at once and reads their replies in order.
+func (r *Redis) processPipeline(ctx context.Context, cn *pool.Conn, cmds []Cmder) error {
+	if err := cn.WithWrite(ctx, func(ctx context.Context, wd *bufio.Writer) error {
+		writer := proto.AcquireWriter(wd)
+		defer proto.Release(writer)
+		for _, cmd := range cmds {
+			if err := writer.Write(ctx, cmd.Args()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		cn.MarkBroken()
+		setCmdsErr(cmds, err)
+		return err
+	}
+
+	return cn.WithRead(ctx, func(ctx context.Context, rd *bufio.Reader) error {
+		reader := proto.AcquireReader(rd)
+		defer proto.Release(reader)
+
+		var firstErr error
+		for i, cmd := range cmds {
+			err := r.readReply(ctx, reader, cmd)
+			if isBadConn(err) {
+				cn.MarkBroken()
+				setCmdsErr(cmds[i:], err)
+				return err
+			}
+			setCmdErr(cmd, err)
+			if err != nil && err != proto.Nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	})
+}
@@ -0,0 +1,73 @@
+package go_redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/mingolm/go-redis/proto"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+	dialer := fakeServer(func(cn net.Conn, args []string) {
+		switch args[0] {
+		case "HELLO":
+			fmt.Fprint(cn, "-ERR unknown command 'HELLO'\r\n")
+		case "GET":
+			if args[1] == "missing" {
+				fmt.Fprint(cn, "$-1\r\n")
+				return
+			}
+			fmt.Fprintf(cn, "$%d\r\n%s\r\n", len(args[1]), args[1])
+		case "LPUSH":
+			fmt.Fprint(cn, "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n")
+		default:
+			fmt.Fprint(cn, "+OK\r\n")
+		}
+	})
+	r := NewClient(&Options{Dialer: dialer, PoolSize: 1, MinIdleConns: 1, MaxIdleConns: 1})
+	ctx := context.Background()
+
+	pipe := r.Pipeline()
+	set := pipe.Set(ctx, "k", "v", time.Minute)
+	missing := pipe.Get(ctx, "missing")
+	push := pipe.LPush(ctx, "k", "x")
+	get := pipe.Get(ctx, "k")
+	if pipe.Len() != 4 {
+		t.Fatalf("got %d queued commands, want 4", pipe.Len())
+	}
+
+	cmds, err := pipe.Exec(ctx)
+	if !proto.IsRedisError(err) {
+		t.Fatalf("got %v, want the LPUSH error", err)
+	}
+	if len(cmds) != 4 || pipe.Len() != 0 {
+		t.Fatalf("got %d commands and %d queued", len(cmds), pipe.Len())
+	}
+	if err := set.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := missing.Err(); err != proto.Nil {
+		t.Fatalf("got %v, want Nil", err)
+	}
+	if push.Err() != err {
+		t.Fatalf("got %v, want %v", push.Err(), err)
+	}
+	// the replies following an error are read too
+	if val, err := get.Result(); err != nil || val != "k" {
+		t.Fatalf("got %q, %v", val, err)
+	}
+
+	cmds, err = r.Pipelined(ctx, func(p *Pipeline) error {
+		p.Get(ctx, "a")
+		p.Get(ctx, "b")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, _ := cmds[1].(*StringCmd).Result(); val != "b" {
+		t.Fatalf("got %q, want b", val)
+	}
+}
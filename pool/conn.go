@@ -22,23 +22,38 @@ type Conn struct {
 	reader    *bufio.Reader
 	writer    *bufio.Writer
 	typ       connTyp
+	broken    bool  // 读写中断, 不可复用
+	written   int64 // bytes sent by the last WithWrite
 	createdAt time.Time
 	usedAt    time.Time
 }
 
 func NewConnect(conn net.Conn) *Conn {
-	return &Conn{
+	c := &Conn{
 		netConn:   conn,
 		reader:    bufio.NewReader(conn),
-		writer:    bufio.NewWriter(conn),
 		createdAt: time.Now(),
 		usedAt:    time.Now(),
 	}
+	c.writer = bufio.NewWriter(connWriter{c})
+	return c
+}
+
+// connWriter counts the bytes written to the network connection.
+type connWriter struct {
+	c *Conn
+}
+
+func (w connWriter) Write(p []byte) (int, error) {
+	n, err := w.c.netConn.Write(p)
+	w.c.written += int64(n)
+	return n, err
 }
 
 func (c *Conn) WithWrite(ctx context.Context, wf func(context.Context, *bufio.Writer) error) error {
+	c.written = 0
 	defer func() {
-		c.writer.Reset(c.netConn)
+		c.writer.Reset(connWriter{c})
 	}()
 
 	if err := wf(ctx, c.writer); err != nil {
@@ -61,6 +76,23 @@ func (c *Conn) WithRead(ctx context.Context, rf func(context.Context, *bufio.Rea
 	return nil
 }
 
+// MarkBroken flags a connection left in an unknown protocol state, e.g. a
+// request or reply interrupted halfway; it is closed instead of reused.
+func (c *Conn) MarkBroken() {
+	c.broken = true
+}
+
+// Written returns the number of bytes the last WithWrite sent to the
+// network. A failed request of which no byte was sent can't have reached
+// the server.
+func (c *Conn) Written() int64 {
+	return c.written
+}
+
+func (c *Conn) Close() error {
+	return c.netConn.Close()
+}
+
 func (c *Conn) check() error {
 	// Reset previous timeout.
 	_ = c.netConn.SetDeadline(time.Time{})
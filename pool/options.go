@@ -14,5 +14,6 @@ type Options struct {
 	MaxIdleConns    int32                                   // 最大空闲连接
 	ConnMaxIdleTime time.Duration                           // 连接超时时间
 	ConnMaxLifetime time.Duration                           // 连接最大生命周期
+	OnConnect       func(context.Context, *Conn) error      // 新连接初始化
 	Logger          *zap.SugaredLogger                      // 日志 debug
 }
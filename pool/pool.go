@@ -94,7 +94,7 @@ func (p *pool) Get(ctx context.Context) (*Conn, error) {
 }
 
 func (p *pool) Put(ctx context.Context, conn *Conn) error {
-	if conn.typ == connTypTmp || !p.connHealthCheck(conn) {
+	if conn.typ == connTypTmp || conn.broken || !p.connHealthCheck(conn) {
 		return p.connClose(conn)
 	}
 
@@ -111,6 +111,7 @@ func (p *pool) addConnect() error {
 	for cur := p.poolSize.Add(1); cur <= p.Options.PoolSize; {
 		conn, err := p.Dialer(context.TODO())
 		if err != nil {
+			p.poolSize.Add(-1)
 			return err
 		}
 		var typ connTyp
@@ -125,6 +126,14 @@ func (p *pool) addConnect() error {
 		cn := NewConnect(conn)
 		cn.typ = typ
 
+		if p.OnConnect != nil {
+			if err = p.OnConnect(context.TODO(), cn); err != nil {
+				_ = conn.Close()
+				p.poolSize.Add(-1)
+				return err
+			}
+		}
+
 		select {
 		case p.conns <- cn:
 			return nil
@@ -141,6 +150,7 @@ func (p *pool) addConnect() error {
 		}
 	}
 
+	p.poolSize.Add(-1)
 	return ErrMaxPoolSize
 }
 
@@ -166,10 +176,9 @@ func (p *pool) connHealthCheck(conn *Conn) bool {
 }
 
 func (p *pool) connClose(conn *Conn) error {
-	if err := conn.netConn.Close(); err != nil {
+	if err := conn.Close(); err != nil {
 		return err
 	}
-	conn = nil
 	if cur := p.poolSize.Add(-1); cur < p.MinIdleConns {
 		if err := p.addConnect(); err != nil {
 			return err
@@ -0,0 +1,111 @@
+package proto
+
+import (
+	"encoding"
+	"fmt"
+	"github.com/mingolm/go-redis/internal/structtag"
+	"net"
+	"reflect"
+	"time"
+)
+
+// AppendArgs appends args to dst, expanding composite values into
+// consecutive arguments:
+//
+//   - slices and arrays (but []byte) into their elements
+//   - maps into key, value, key, value, ...
+//   - structs, or pointers to them, into name, value pairs of the fields
+//     tagged `redis:"name"`; `redis:"name,omitempty"` skips zero values and
+//     `redis:"-"` skips the field
+//
+// Only args themselves are expanded, not the elements, values or fields
+// they hold. Values implementing encoding.BinaryMarshaler,
+// encoding.TextMarshaler or fmt.Stringer are kept as a single argument.
+//
+// Commands taking key/value lists, such as MSET and HSET, call it on
+// their variadic values; other arguments are written as is.
+func AppendArgs(dst []interface{}, args ...interface{}) []interface{} {
+	for _, arg := range args {
+		dst = appendArg(dst, arg)
+	}
+	return dst
+}
+
+func appendArg(dst []interface{}, arg interface{}) []interface{} {
+	switch v := arg.(type) {
+	case []string:
+		for _, s := range v {
+			dst = append(dst, s)
+		}
+		return dst
+	case []interface{}:
+		return append(dst, v...)
+	case map[string]interface{}:
+		for k, e := range v {
+			dst = append(dst, k, e)
+		}
+		return dst
+	case map[string]string:
+		for k, e := range v {
+			dst = append(dst, k, e)
+		}
+		return dst
+	}
+	if !isComposite(arg) {
+		return append(dst, arg)
+	}
+
+	rv := reflect.ValueOf(arg)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			dst = append(dst, rv.Index(i).Interface())
+		}
+	case reflect.Map:
+		iter := rv.MapRange()
+		for iter.Next() {
+			dst = append(dst, iter.Key().Interface(), iter.Value().Interface())
+		}
+	case reflect.Pointer:
+		if !rv.IsNil() {
+			dst = appendStruct(dst, rv.Elem())
+		}
+	case reflect.Struct:
+		dst = appendStruct(dst, rv)
+	}
+	return dst
+}
+
+func appendStruct(dst []interface{}, rv reflect.Value) []interface{} {
+	for _, f := range structtag.Fields(rv.Type()) {
+		fv := rv.Field(f.Index)
+		if f.OmitEmpty && fv.IsZero() {
+			continue
+		}
+		dst = append(dst, f.Name, fv.Interface())
+	}
+	return dst
+}
+
+func isComposite(arg interface{}) bool {
+	switch arg.(type) {
+	case nil, string, []byte, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64, bool,
+		time.Time, time.Duration, net.IP, *BulkReader,
+		encoding.BinaryMarshaler, encoding.TextMarshaler, fmt.Stringer:
+		return false
+	case []string, []interface{}, map[string]interface{}, map[string]string:
+		return true
+	}
+
+	t := reflect.TypeOf(arg)
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return t.Elem().Kind() != reflect.Uint8
+	case reflect.Map, reflect.Struct:
+		return true
+	case reflect.Pointer:
+		return t.Elem().Kind() == reflect.Struct
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package proto
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type point struct{ x, y int }
+
+func (p point) String() string { return "point" }
+
+type user struct {
+	Name    string    `redis:"name"`
+	Age     int       `redis:"age,omitempty"`
+	Tags    []string  `redis:"-"`
+	Created time.Time `redis:"created,omitempty"`
+	Ignored string
+}
+
+func TestAppendArgs(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		in   []interface{}
+		want []interface{}
+	}{
+		{[]interface{}{"k", []string{"a", "b"}}, []interface{}{"k", "a", "b"}},
+		// only one level is expanded
+		{[]interface{}{[]interface{}{1, []string{"x"}}}, []interface{}{1, []string{"x"}}},
+		{[]interface{}{map[string]string{"f": "v"}}, []interface{}{"f", "v"}},
+		{[]interface{}{map[string]interface{}{"f": []string{"a", "b"}}}, []interface{}{"f", []string{"a", "b"}}},
+		{[]interface{}{map[string][]int{"f": {1}}}, []interface{}{"f", []int{1}}},
+		{[]interface{}{[]int{1, 2}}, []interface{}{1, 2}},
+		{[]interface{}{[]byte("raw")}, []interface{}{[]byte("raw")}},
+		{[]interface{}{user{Name: "mingo", Tags: []string{"t"}}}, []interface{}{"name", "mingo"}},
+		{[]interface{}{&user{Name: "mingo", Age: 18, Created: ts}}, []interface{}{"name", "mingo", "age", 18, "created", ts}},
+		{[]interface{}{(*user)(nil)}, nil},
+		{[]interface{}{ts, point{}}, []interface{}{ts, point{}}},
+	}
+	for _, tt := range tests {
+		if got := AppendArgs(nil, tt.in...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AppendArgs(%v): got %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteFlattened(t *testing.T) {
+	var buf bytes.Buffer
+	wd := bufio.NewWriter(&buf)
+	args := AppendArgs([]interface{}{"HSET", "user:1"}, user{Name: "mingo", Age: 18}, point{})
+	if err := NewWriter(wd).Write(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+	_ = wd.Flush()
+
+	want := "*7\r\n$4\r\nHSET\r\n$6\r\nuser:1\r\n$4\r\nname\r\n$5\r\nmingo\r\n$3\r\nage\r\n$2\r\n18\r\n$5\r\npoint\r\n"
+	if buf.String() != want {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+}
+
+// Write doesn't flatten: a composite value is an error, not several
+// arguments.
+func TestWriteComposite(t *testing.T) {
+	for _, arg := range []interface{}{[]string{"v", "NX"}, map[string]string{"f": "v"}, user{Name: "mingo"}} {
+		var buf bytes.Buffer
+		wd := bufio.NewWriter(&buf)
+		if err := NewWriter(wd).Write(context.Background(), []interface{}{"SET", "k", arg}); err == nil {
+			t.Errorf("%T written", arg)
+		}
+	}
+}
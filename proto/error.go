@@ -1,12 +1,220 @@
 package proto
 
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
 var (
 	Nil            = RedisError("redis: nil")
 	UnexpectedData = RedisError("redis: unexpected data")
 )
 
+// Sentinels of the typed server errors, for use with errors.Is:
+//
+//	if errors.Is(err, proto.ErrWrongType) {
+//	}
+//
+// Use errors.As to get the details, e.g. the slot of a *MovedError.
+var (
+	ErrMoved     error = &MovedError{}
+	ErrAsk       error = &AskError{}
+	ErrWrongType error = &WrongTypeError{}
+	ErrNoAuth    error = &NoAuthError{}
+	ErrNoPerm    error = &NoPermError{}
+	ErrLoading   error = &LoadingError{}
+	ErrBusy      error = &BusyError{}
+	ErrReadOnly  error = &ReadOnlyError{}
+	ErrTryAgain  error = &TryAgainError{}
+	ErrExecAbort error = &ExecAbortError{}
+	ErrOOM       error = &OOMError{}
+)
+
+// RedisError is an error reply of the server. Typed errors below wrap it,
+// so errors.As(err, &RedisError) matches every server error.
 type RedisError string
 
 func (e RedisError) Error() string {
 	return string(e)
 }
+
+// MovedError: the key's hash slot is served by another cluster node.
+type MovedError struct {
+	RedisError
+	Slot int
+	Addr string
+}
+
+func (e *MovedError) Unwrap() error { return e.RedisError }
+
+func (e *MovedError) Is(target error) bool {
+	_, ok := target.(*MovedError)
+	return ok
+}
+
+// AskError: the hash slot is being migrated, retry the command on Addr
+// after ASKING.
+type AskError struct {
+	RedisError
+	Slot int
+	Addr string
+}
+
+func (e *AskError) Unwrap() error { return e.RedisError }
+
+func (e *AskError) Is(target error) bool {
+	_, ok := target.(*AskError)
+	return ok
+}
+
+// WrongTypeError: the operation was run against a key holding another type.
+type WrongTypeError struct{ RedisError }
+
+func (e *WrongTypeError) Unwrap() error { return e.RedisError }
+
+func (e *WrongTypeError) Is(target error) bool {
+	_, ok := target.(*WrongTypeError)
+	return ok
+}
+
+// NoAuthError: authentication is required.
+type NoAuthError struct{ RedisError }
+
+func (e *NoAuthError) Unwrap() error { return e.RedisError }
+
+func (e *NoAuthError) Is(target error) bool {
+	_, ok := target.(*NoAuthError)
+	return ok
+}
+
+// NoPermError: the ACL user has no permission for the command, key or channel.
+type NoPermError struct{ RedisError }
+
+func (e *NoPermError) Unwrap() error { return e.RedisError }
+
+func (e *NoPermError) Is(target error) bool {
+	_, ok := target.(*NoPermError)
+	return ok
+}
+
+// LoadingError: the server is loading the dataset in memory.
+type LoadingError struct{ RedisError }
+
+func (e *LoadingError) Unwrap() error { return e.RedisError }
+
+func (e *LoadingError) Is(target error) bool {
+	_, ok := target.(*LoadingError)
+	return ok
+}
+
+// BusyError: the server is busy running a script or function.
+type BusyError struct{ RedisError }
+
+func (e *BusyError) Unwrap() error { return e.RedisError }
+
+func (e *BusyError) Is(target error) bool {
+	_, ok := target.(*BusyError)
+	return ok
+}
+
+// ReadOnlyError: a write was sent to a read-only replica.
+type ReadOnlyError struct{ RedisError }
+
+func (e *ReadOnlyError) Unwrap() error { return e.RedisError }
+
+func (e *ReadOnlyError) Is(target error) bool {
+	_, ok := target.(*ReadOnlyError)
+	return ok
+}
+
+// TryAgainError: a multi-key command hit a slot being resharded.
+type TryAgainError struct{ RedisError }
+
+func (e *TryAgainError) Unwrap() error { return e.RedisError }
+
+func (e *TryAgainError) Is(target error) bool {
+	_, ok := target.(*TryAgainError)
+	return ok
+}
+
+// ExecAbortError: EXEC was discarded because of previous errors.
+type ExecAbortError struct{ RedisError }
+
+func (e *ExecAbortError) Unwrap() error { return e.RedisError }
+
+func (e *ExecAbortError) Is(target error) bool {
+	_, ok := target.(*ExecAbortError)
+	return ok
+}
+
+// OOMError: the command is not allowed when used memory > maxmemory.
+type OOMError struct{ RedisError }
+
+func (e *OOMError) Unwrap() error { return e.RedisError }
+
+func (e *OOMError) Is(target error) bool {
+	_, ok := target.(*OOMError)
+	return ok
+}
+
+// IsRedisError reports whether err is an error reply of the server, as
+// opposed to a network or protocol error.
+func IsRedisError(err error) bool {
+	var rerr RedisError
+	return errors.As(err, &rerr)
+}
+
+// ParseError maps an error reply to its typed error by prefix; unknown
+// prefixes are returned as a plain RedisError.
+func ParseError(msg string) error {
+	rerr := RedisError(msg)
+	prefix, rest, _ := strings.Cut(msg, " ")
+	switch prefix {
+	case "MOVED":
+		slot, addr, ok := parseRedirect(rest)
+		if !ok {
+			return rerr
+		}
+		return &MovedError{RedisError: rerr, Slot: slot, Addr: addr}
+	case "ASK":
+		slot, addr, ok := parseRedirect(rest)
+		if !ok {
+			return rerr
+		}
+		return &AskError{RedisError: rerr, Slot: slot, Addr: addr}
+	case "WRONGTYPE":
+		return &WrongTypeError{rerr}
+	case "NOAUTH":
+		return &NoAuthError{rerr}
+	case "NOPERM":
+		return &NoPermError{rerr}
+	case "LOADING":
+		return &LoadingError{rerr}
+	case "BUSY":
+		return &BusyError{rerr}
+	case "READONLY":
+		return &ReadOnlyError{rerr}
+	case "TRYAGAIN":
+		return &TryAgainError{rerr}
+	case "EXECABORT":
+		return &ExecAbortError{rerr}
+	case "OOM":
+		return &OOMError{rerr}
+	default:
+		return rerr
+	}
+}
+
+// parseRedirect parses "<slot> <host:port>" of MOVED and ASK.
+func parseRedirect(s string) (int, string, bool) {
+	slot, addr, ok := strings.Cut(s, " ")
+	if !ok {
+		return 0, "", false
+	}
+	n, err := strconv.Atoi(slot)
+	if err != nil {
+		return 0, "", false
+	}
+	return n, addr, true
+}
@@ -0,0 +1,50 @@
+package proto
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	err := ParseError("MOVED 3999 127.0.0.1:6381")
+	var moved *MovedError
+	if !errors.As(err, &moved) {
+		t.Fatalf("got %T, want *MovedError", err)
+	}
+	if moved.Slot != 3999 || moved.Addr != "127.0.0.1:6381" {
+		t.Fatalf("got slot %d addr %s", moved.Slot, moved.Addr)
+	}
+	if err.Error() != "MOVED 3999 127.0.0.1:6381" {
+		t.Fatalf("got message %q", err.Error())
+	}
+
+	tests := map[string]error{
+		"WRONGTYPE Operation against a key holding the wrong kind of value": ErrWrongType,
+		"NOAUTH Authentication required.":                                   ErrNoAuth,
+		"NOPERM User default has no permissions to run the 'get' command":   ErrNoPerm,
+		"LOADING Redis is loading the dataset in memory":                    ErrLoading,
+		"BUSY Redis is busy running a script.":                              ErrBusy,
+		"READONLY You can't write against a read only replica.":             ErrReadOnly,
+		"TRYAGAIN Multiple keys request during rehashing of slot":           ErrTryAgain,
+		"EXECABORT Transaction discarded because of previous errors.":       ErrExecAbort,
+		"OOM command not allowed when used memory > 'maxmemory'.":           ErrOOM,
+		"ASK 3999 127.0.0.1:6381":                                           ErrAsk,
+	}
+	for msg, want := range tests {
+		err := ParseError(msg)
+		if !errors.Is(err, want) {
+			t.Errorf("%q: got %T, want %T", msg, err, want)
+		}
+		if errors.Is(err, ErrMoved) {
+			t.Errorf("%q: unexpectedly matches ErrMoved", msg)
+		}
+		var rerr RedisError
+		if !errors.As(err, &rerr) || string(rerr) != msg {
+			t.Errorf("%q: got RedisError %q", msg, rerr)
+		}
+	}
+
+	if err := ParseError("ERR unknown command"); err != RedisError("ERR unknown command") {
+		t.Fatalf("got %#v, want plain RedisError", err)
+	}
+}
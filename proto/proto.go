@@ -2,7 +2,9 @@ package proto
 
 import (
 	"bufio"
+	"io"
 	"strconv"
+	"sync"
 )
 
 const (
@@ -21,11 +23,28 @@ const (
 	RespSet       = '~' // ~<len>\r\n... (same as Array)
 	RespAttr      = '|' // |<len>\r\n(key)\r\n(value)\r\n... + command reply
 	RespPush      = '>' // ><len>\r\n... (same as Array)
+	RespChunk     = ';' // ;<length>\r\n<bytes>\r\n part of a streamed string ($?\r\n...;0\r\n)
+	RespStreamEnd = '.' // .\r\n terminates a streamed aggregate (<type>?\r\n...)
 )
 
 type RESP struct {
 	Writer *bufio.Writer
 	Reader *bufio.Reader
+	// OnAttributes receives the attributes sent along with a reply.
+	// Without it attributes are discarded.
+	OnAttributes func(attrs map[interface{}]interface{})
+
+	// scratch space of the writer, for the length prefix and the text
+	// form of numeric arguments
+	lenBuf [24]byte
+	argBuf [64]byte
+}
+
+// BulkReader is a command argument streamed from R, which must yield
+// exactly Size bytes.
+type BulkReader struct {
+	R    io.Reader
+	Size int64
 }
 
 func NewWriter(wd *bufio.Writer) *RESP {
@@ -40,6 +59,57 @@ func NewReader(rd *bufio.Reader) *RESP {
 	}
 }
 
+var respPool = sync.Pool{
+	New: func() interface{} {
+		return new(RESP)
+	},
+}
+
+// AcquireWriter is NewWriter backed by a pool, see Release.
+func AcquireWriter(wd *bufio.Writer) *RESP {
+	w := respPool.Get().(*RESP)
+	w.Writer = wd
+	return w
+}
+
+// AcquireReader is NewReader backed by a pool, see Release.
+func AcquireReader(rd *bufio.Reader) *RESP {
+	w := respPool.Get().(*RESP)
+	w.Reader = rd
+	return w
+}
+
+// Release returns w to the pool; w must not be used afterwards.
+func Release(w *RESP) {
+	w.Writer = nil
+	w.Reader = nil
+	w.OnAttributes = nil
+	respPool.Put(w)
+}
+
+// scratch buffers of bulk payloads that are copied out right away
+var bufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 512)
+		return &b
+	},
+}
+
+// buffers grown past this size are left to the GC instead of being pooled
+const maxPooledBuf = 64 << 10
+
+func getBuf() *[]byte {
+	return bufPool.Get().(*[]byte)
+}
+
+func putBuf(b *[]byte) {
+	if cap(*b) > maxPooledBuf {
+		return
+	}
+	*b = (*b)[:0]
+	bufPool.Put(b)
+}
+
 func byteToInt(bs []byte) (int64, error) {
 	return strconv.ParseInt(string(bs), 10, 64)
 }
@@ -3,27 +3,45 @@ package proto
 import (
 	"bufio"
 	"fmt"
+	"io"
+	"math"
 	"math/big"
 	"strconv"
 )
 
+// VerbatimString is a resp3 verbatim string, e.g. the txt formatted reply
+// of INFO or LATENCY DOCTOR.
+type VerbatimString struct {
+	Format string
+	Text   string
+}
+
+func (v VerbatimString) String() string {
+	return v.Text
+}
+
 func (w *RESP) Read() (interface{}, error) {
 	seg, err := w.readLine()
 	if err != nil {
 		return nil, err
 	}
+	return w.readValue(seg)
+}
 
+// readValue reads the reply whose first line is seg. seg may point into
+// the bufio buffer, so it is parsed before anything else is read.
+func (w *RESP) readValue(seg []byte) (interface{}, error) {
 	switch typ := seg[0]; typ {
 	case RespNil:
 		return nil, Nil
 	case RespError:
-		return nil, RedisError(seg[1:])
+		return nil, ParseError(string(seg[1:]))
 	case RespStatus:
 		return string(seg[1:]), nil
 	case RespInt:
 		return strconv.ParseInt(string(seg[1:]), 10, 64)
 	case RespFloat:
-		return strconv.ParseFloat(string(seg[1:]), 64)
+		return parseFloat(seg[1:])
 	case RespBool:
 		switch string(seg[1:]) {
 		case "t":
@@ -33,12 +51,15 @@ func (w *RESP) Read() (interface{}, error) {
 		}
 		return nil, UnexpectedData
 	case RespBigInt:
-		i := new(big.Int)
-		if i, ok := i.SetString(string(seg[1:]), 10); ok {
-			return i, nil
+		i, ok := new(big.Int).SetString(string(seg[1:]), 10)
+		if !ok {
+			return nil, fmt.Errorf("redis: invalid big number %q", seg[1:])
 		}
 		return i, nil
 	case RespString:
+		if isStreamed(seg) {
+			return w.readStreamedString()
+		}
 		n, err := byteToInt(seg[1:])
 		if err != nil {
 			return nil, err
@@ -47,32 +68,98 @@ func (w *RESP) Read() (interface{}, error) {
 		if n == -1 {
 			return nil, Nil
 		}
-		bs := make([]byte, n)
-		rn, err := w.Reader.Read(bs)
+		return w.readBulkString(n)
+	case RespBlobError:
+		n, err := byteToInt(seg[1:])
 		if err != nil {
 			return nil, err
-		} else if n != int64(rn) {
-			return nil, UnexpectedData
 		}
-
-		return string(bs), nil
+		bs, err := w.readBulk(n)
+		if err != nil {
+			return nil, err
+		}
+		return nil, ParseError(string(bs))
+	case RespVerbatim:
+		n, err := byteToInt(seg[1:])
+		if err != nil {
+			return nil, err
+		}
+		bs, err := w.readBulk(n)
+		if err != nil {
+			return nil, err
+		}
+		// 3 bytes format followed by ':'
+		if len(bs) < 4 || bs[3] != ':' {
+			return nil, fmt.Errorf("redis: invalid verbatim string %q", bs)
+		}
+		return VerbatimString{
+			Format: string(bs[:3]),
+			Text:   string(bs[4:]),
+		}, nil
 	case RespArray, RespSet, RespPush:
+		if isStreamed(seg) {
+			return w.readStreamedSlice()
+		}
 		n, err := byteToInt(seg[1:])
 		if err != nil {
 			return nil, err
 		}
+		// -1: null array (resp2)
+		if n == -1 {
+			return nil, Nil
+		}
 		return w.readSlice(n)
 	case RespMap:
+		if isStreamed(seg) {
+			return w.readStreamedMap()
+		}
 		n, err := byteToInt(seg[1:])
 		if err != nil {
 			return nil, err
 		}
 		return w.readMap(n)
+	case RespAttr:
+		if err := w.readAttributes(seg); err != nil {
+			if !IsRedisError(err) {
+				return nil, err
+			}
+			// still read the reply they annotate
+			if _, rerr := w.Read(); rerr != nil && rerr != Nil && !IsRedisError(rerr) {
+				return nil, rerr
+			}
+			return nil, err
+		}
+		return w.Read()
 	default:
 		return nil, fmt.Errorf("redis: unknwon proto %s", string(typ))
 	}
 }
 
+// readAttributes reads the attributes introduced by seg and passes them
+// to OnAttributes; they only annotate the reply that follows them.
+func (w *RESP) readAttributes(seg []byte) error {
+	var (
+		attrs map[interface{}]interface{}
+		err   error
+	)
+	if isStreamed(seg) {
+		attrs, err = w.readStreamedMap()
+	} else {
+		var n int64
+		if n, err = byteToInt(seg[1:]); err != nil {
+			return err
+		}
+		attrs, err = w.readMap(n)
+	}
+	if err != nil {
+		return err
+	}
+	if w.OnAttributes != nil {
+		w.OnAttributes(attrs)
+	}
+	return nil
+}
+
 func (w *RESP) readLine() ([]byte, error) {
 	b, err := w.Reader.ReadSlice('\n')
 	if err != nil {
@@ -97,19 +184,106 @@ func (w *RESP) readLine() ([]byte, error) {
 	return b[:len(b)-2], nil
 }
 
+// readBulk reads a n bytes payload and its trailing CRLF.
+func (w *RESP) readBulk(n int64) ([]byte, error) {
+	return w.readBulkInto(nil, n)
+}
+
+// readBulkString reads a bulk payload through a pooled scratch buffer,
+// so the returned string is the only allocation.
+func (w *RESP) readBulkString(n int64) (string, error) {
+	buf := getBuf()
+	defer putBuf(buf)
+
+	bs, err := w.readBulkInto(*buf, n)
+	*buf = bs
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
+
+// readBulkInto reads a n bytes payload into buf, reusing its capacity,
+// and consumes the trailing CRLF.
+func (w *RESP) readBulkInto(buf []byte, n int64) ([]byte, error) {
+	if n < 0 {
+		return buf[:0], fmt.Errorf("redis: invalid bulk length %d", n)
+	}
+	if int64(cap(buf)) < n {
+		buf = make([]byte, n)
+	}
+	buf = buf[:n]
+	if _, err := io.ReadFull(w.Reader, buf); err != nil {
+		return buf[:0], err
+	}
+	if err := w.readCRLF(); err != nil {
+		return buf[:0], err
+	}
+	return buf, nil
+}
+
+func (w *RESP) readCRLF() error {
+	cr, err := w.Reader.ReadByte()
+	if err != nil {
+		return err
+	}
+	lf, err := w.Reader.ReadByte()
+	if err != nil {
+		return err
+	}
+	if cr != '\r' || lf != '\n' {
+		return UnexpectedData
+	}
+	return nil
+}
+
+// readStreamedString reads ;<len> chunks until the ;0 terminator.
+func (w *RESP) readStreamedString() (string, error) {
+	var bs []byte
+	for {
+		seg, err := w.readLine()
+		if err != nil {
+			return "", err
+		}
+		if seg[0] != RespChunk {
+			return "", fmt.Errorf("redis: invalid streamed string chunk %q", seg)
+		}
+		n, err := byteToInt(seg[1:])
+		if err != nil {
+			return "", err
+		}
+		if n == 0 {
+			return string(bs), nil
+		}
+		chunk, err := w.readBulk(n)
+		if err != nil {
+			return "", err
+		}
+		bs = append(bs, chunk...)
+	}
+}
+
+// readElem reads an element of an aggregate, where null and error
+// replies are values rather than a failure of the whole reply.
+func (w *RESP) readElem() (interface{}, error) {
+	v, err := w.Read()
+	if err != nil {
+		if err == Nil {
+			return nil, nil
+		}
+		if IsRedisError(err) {
+			return err, nil
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
 func (w *RESP) readSlice(n int64) ([]interface{}, error) {
 	val := make([]interface{}, n)
 	for i := 0; i < len(val); i++ {
-		v, err := w.Read()
+		v, err := w.readElem()
 		if err != nil {
-			if err == Nil {
-				val[i] = nil
-				continue
-			}
-			if err, ok := err.(RedisError); ok {
-				val[i] = err
-				continue
-			}
 			return nil, err
 		}
 		val[i] = v
@@ -119,24 +293,113 @@ func (w *RESP) readSlice(n int64) ([]interface{}, error) {
 
 func (w *RESP) readMap(n int64) (map[interface{}]interface{}, error) {
 	m := make(map[interface{}]interface{}, n)
-	for i := 0; i < len(m); i++ {
-		k, err := w.Read()
+	var keyErr error
+	for i := int64(0); i < n; i++ {
+		k, err := w.readElem()
 		if err != nil {
 			return nil, err
 		}
-		v, err := w.Read()
+		v, err := w.readElem()
 		if err != nil {
-			if err == Nil {
-				m[k] = nil
-				continue
-			}
-			if err, ok := err.(RedisError); ok {
-				m[k] = err
-				continue
-			}
 			return nil, err
 		}
-		m[k] = v
+		if err := setKey(m, k, v); err != nil && keyErr == nil {
+			keyErr = err
+		}
+	}
+	if keyErr != nil {
+		return nil, keyErr
 	}
 	return m, nil
 }
+
+// setKey sets m[k] = v, unless k is an aggregate: valid resp3, but not
+// comparable in Go. The caller still reads the rest of the map, so that
+// the connection stays in sync.
+func setKey(m map[interface{}]interface{}, k, v interface{}) error {
+	switch k.(type) {
+	case []interface{}, map[interface{}]interface{}:
+		return RedisError(fmt.Sprintf("redis: unsupported map key of type %T", k))
+	}
+	m[k] = v
+	return nil
+}
+
+func (w *RESP) readStreamedSlice() ([]interface{}, error) {
+	var val []interface{}
+	for {
+		end, err := w.readEnd()
+		if err != nil {
+			return nil, err
+		}
+		if end {
+			return val, nil
+		}
+		v, err := w.readElem()
+		if err != nil {
+			return nil, err
+		}
+		val = append(val, v)
+	}
+}
+
+func (w *RESP) readStreamedMap() (map[interface{}]interface{}, error) {
+	m := make(map[interface{}]interface{})
+	var keyErr error
+	for {
+		end, err := w.readEnd()
+		if err != nil {
+			return nil, err
+		}
+		if end {
+			if keyErr != nil {
+				return nil, keyErr
+			}
+			return m, nil
+		}
+		k, err := w.readElem()
+		if err != nil {
+			return nil, err
+		}
+		v, err := w.readElem()
+		if err != nil {
+			return nil, err
+		}
+		if err := setKey(m, k, v); err != nil && keyErr == nil {
+			keyErr = err
+		}
+	}
+}
+
+// readEnd consumes the . terminator of a streamed aggregate, if next.
+func (w *RESP) readEnd() (bool, error) {
+	b, err := w.Reader.Peek(1)
+	if err != nil {
+		return false, err
+	}
+	if b[0] != RespStreamEnd {
+		return false, nil
+	}
+	if _, err = w.readLine(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isStreamed reports a resp3 streamed type of unknown length, e.g. $? or *?.
+func isStreamed(seg []byte) bool {
+	return len(seg) == 2 && seg[1] == '?'
+}
+
+// parseFloat also accepts the inf, -inf and nan doubles of resp3.
+func parseFloat(bs []byte) (float64, error) {
+	switch string(bs) {
+	case "inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(string(bs), 64)
+}
@@ -0,0 +1,102 @@
+package proto
+
+import (
+	"bufio"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func read(t *testing.T, s string) (interface{}, error) {
+	t.Helper()
+	return NewReader(bufio.NewReader(strings.NewReader(s))).Read()
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		in   string
+		want interface{}
+	}{
+		{"*2\r\n$3\r\nfoo\r\n$3\r\nbar\r\n", []interface{}{"foo", "bar"}},
+		{"*3\r\n$1\r\na\r\n$-1\r\n_\r\n", []interface{}{"a", nil, nil}},
+		{"=15\r\ntxt:Some string\r\n", VerbatimString{Format: "txt", Text: "Some string"}},
+		{"$?\r\n;4\r\nHell\r\n;5\r\no wor\r\n;1\r\nd\r\n;0\r\n", "Hello word"},
+		{"*?\r\n:1\r\n$1\r\na\r\n.\r\n", []interface{}{int64(1), "a"}},
+		{"%?\r\n+a\r\n:1\r\n+b\r\n:2\r\n.\r\n", map[interface{}]interface{}{"a": int64(1), "b": int64(2)}},
+		{"%1\r\n+k\r\n*1\r\n:1\r\n", map[interface{}]interface{}{"k": []interface{}{int64(1)}}},
+		{",inf\r\n", math.Inf(1)},
+		{",-inf\r\n", math.Inf(-1)},
+		{",1.5\r\n", 1.5},
+	}
+	for _, tt := range tests {
+		got, err := read(t, tt.in)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+
+	got, err := read(t, ",nan\r\n")
+	if f, ok := got.(float64); err != nil || !ok || !math.IsNaN(f) {
+		t.Errorf("nan: got %v, %v", got, err)
+	}
+}
+
+func TestReadBlobError(t *testing.T) {
+	_, err := read(t, "!21\r\nSYNTAX invalid syntax\r\n")
+	var rerr RedisError
+	if !errors.As(err, &rerr) || string(rerr) != "SYNTAX invalid syntax" {
+		t.Fatalf("got %v", err)
+	}
+
+	_, err = read(t, "!26\r\nWRONGTYPE wrong value kind\r\n")
+	if !errors.Is(err, ErrWrongType) {
+		t.Fatalf("got %T, want *WrongTypeError", err)
+	}
+}
+
+func TestReadAttributes(t *testing.T) {
+	rd := NewReader(bufio.NewReader(strings.NewReader(
+		"|1\r\n+key-popularity\r\n*2\r\n$1\r\na\r\n,0.19\r\n" +
+			"*2\r\n:2039123\r\n:9543892\r\n",
+	)))
+	var attrs map[interface{}]interface{}
+	rd.OnAttributes = func(a map[interface{}]interface{}) {
+		attrs = a
+	}
+
+	got, err := rd.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{int64(2039123), int64(9543892)}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+	want := map[interface{}]interface{}{"key-popularity": []interface{}{"a", 0.19}}
+	if !reflect.DeepEqual(attrs, want) {
+		t.Fatalf("got attributes %#v, want %#v", attrs, want)
+	}
+}
+
+func TestReadAggregateMapKey(t *testing.T) {
+	for _, in := range []string{
+		"%2\r\n*1\r\n:1\r\n:2\r\n+k\r\n:3\r\n",
+		"%?\r\n%1\r\n+a\r\n:1\r\n:2\r\n.\r\n",
+		"|1\r\n*1\r\n:1\r\n:2\r\n:3\r\n",
+	} {
+		rd := NewReader(bufio.NewReader(strings.NewReader(in + "+NEXT\r\n")))
+		rd.OnAttributes = func(map[interface{}]interface{}) {}
+		if got, err := rd.Read(); err == nil {
+			t.Errorf("%q: got %#v, want error", in, got)
+		}
+		// the whole aggregate is consumed
+		if got, err := rd.Read(); err != nil || got != "NEXT" {
+			t.Errorf("%q: then got %#v, %v", in, got, err)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package proto
+
+import (
+	"fmt"
+	"io"
+)
+
+// The typed readers below decode a reply of an expected shape straight
+// from the connection, without boxing it into interface{} like Read.
+// A reply of another shape is consumed and reported as an error.
+
+// readHeader reads the first line of a reply, passing attributes to
+// OnAttributes and returning null and error replies as errors.
+func (w *RESP) readHeader() ([]byte, error) {
+	for {
+		seg, err := w.readLine()
+		if err != nil {
+			return nil, err
+		}
+		switch seg[0] {
+		case RespAttr:
+			if err := w.readAttributes(seg); err != nil {
+				return nil, err
+			}
+		case RespNil, RespError, RespBlobError:
+			_, err := w.readValue(seg)
+			return nil, err
+		default:
+			return seg, nil
+		}
+	}
+}
+
+// unexpected consumes the reply introduced by seg.
+func (w *RESP) unexpected(seg []byte, want string) error {
+	typ := seg[0]
+	if _, err := w.readValue(seg); err != nil && !IsRedisError(err) {
+		return err
+	}
+	return fmt.Errorf("redis: unexpected reply type %q, want %s", typ, want)
+}
+
+// bulkLen parses the length of a bulk string, null (-1) is Nil.
+func bulkLen(seg []byte) (int64, error) {
+	n, err := byteToInt(seg[1:])
+	if err != nil {
+		return 0, err
+	}
+	if n == -1 {
+		return 0, Nil
+	}
+	return n, nil
+}
+
+// ReadString reads a simple, bulk or verbatim string. Numbers are
+// returned in their text form.
+func (w *RESP) ReadString() (string, error) {
+	seg, err := w.readHeader()
+	if err != nil {
+		return "", err
+	}
+	switch seg[0] {
+	case RespStatus:
+		// the most common status, without allocation
+		if string(seg[1:]) == "OK" {
+			return "OK", nil
+		}
+		return string(seg[1:]), nil
+	case RespInt, RespFloat, RespBigInt:
+		return string(seg[1:]), nil
+	case RespString:
+		if isStreamed(seg) {
+			return w.readStreamedString()
+		}
+		n, err := bulkLen(seg)
+		if err != nil {
+			return "", err
+		}
+		return w.readBulkString(n)
+	case RespVerbatim:
+		v, err := w.readValue(seg)
+		if err != nil {
+			return "", err
+		}
+		return v.(VerbatimString).Text, nil
+	default:
+		return "", w.unexpected(seg, "string")
+	}
+}
+
+// ReadBytes is ReadString for callers that need a []byte: the payload is
+// read into a slice owned by the caller, without an intermediate string.
+func (w *RESP) ReadBytes() ([]byte, error) {
+	seg, err := w.readHeader()
+	if err != nil {
+		return nil, err
+	}
+	switch seg[0] {
+	case RespStatus, RespInt, RespFloat, RespBigInt:
+		return append([]byte(nil), seg[1:]...), nil
+	case RespString:
+		if isStreamed(seg) {
+			s, err := w.readStreamedString()
+			return []byte(s), err
+		}
+		n, err := bulkLen(seg)
+		if err != nil {
+			return nil, err
+		}
+		return w.readBulk(n)
+	case RespVerbatim:
+		n, err := bulkLen(seg)
+		if err != nil {
+			return nil, err
+		}
+		bs, err := w.readBulk(n)
+		if err != nil {
+			return nil, err
+		}
+		if len(bs) < 4 || bs[3] != ':' {
+			return nil, fmt.Errorf("redis: invalid verbatim string %q", bs)
+		}
+		return bs[4:], nil
+	default:
+		return nil, w.unexpected(seg, "string")
+	}
+}
+
+// ReadBulkTo copies a bulk string to dst in chunks, without holding the
+// whole payload in memory, and returns the number of bytes written. If dst
+// fails the rest of the payload is still consumed so the connection stays
+// usable.
+func (w *RESP) ReadBulkTo(dst io.Writer) (int64, error) {
+	seg, err := w.readHeader()
+	if err != nil {
+		return 0, err
+	}
+	if seg[0] != RespString || isStreamed(seg) {
+		return 0, w.unexpected(seg, "bulk string")
+	}
+	n, err := bulkLen(seg)
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := io.CopyN(dst, w.Reader, n)
+	if err != nil {
+		// drain what dst did not take, which fails too if the reader did
+		if _, derr := w.Reader.Discard(int(n - written)); derr != nil {
+			return written, derr
+		}
+		if derr := w.readCRLF(); derr != nil {
+			return written, derr
+		}
+		return written, err
+	}
+	return written, w.readCRLF()
+}
+
+// ReadInt reads an integer, or a bulk string holding one.
+// A resp3 boolean reads as 1 or 0.
+func (w *RESP) ReadInt() (int64, error) {
+	seg, err := w.readHeader()
+	if err != nil {
+		return 0, err
+	}
+	switch seg[0] {
+	case RespInt:
+		return byteToInt(seg[1:])
+	case RespBool:
+		return boolToInt(seg[1:])
+	case RespString:
+		n, err := bulkLen(seg)
+		if err != nil {
+			return 0, err
+		}
+		buf := getBuf()
+		defer putBuf(buf)
+		bs, err := w.readBulkInto(*buf, n)
+		*buf = bs
+		if err != nil {
+			return 0, err
+		}
+		return byteToInt(bs)
+	default:
+		return 0, w.unexpected(seg, "int")
+	}
+}
+
+// ReadFloat reads a double, an integer, or a bulk string holding either.
+func (w *RESP) ReadFloat() (float64, error) {
+	seg, err := w.readHeader()
+	if err != nil {
+		return 0, err
+	}
+	switch seg[0] {
+	case RespFloat, RespInt:
+		return parseFloat(seg[1:])
+	case RespString:
+		n, err := bulkLen(seg)
+		if err != nil {
+			return 0, err
+		}
+		buf := getBuf()
+		defer putBuf(buf)
+		bs, err := w.readBulkInto(*buf, n)
+		*buf = bs
+		if err != nil {
+			return 0, err
+		}
+		return parseFloat(bs)
+	default:
+		return 0, w.unexpected(seg, "float")
+	}
+}
+
+// ReadArrayLen reads the header of an array, set or push reply; the
+// caller must then read every element. A null array is Nil.
+func (w *RESP) ReadArrayLen() (int, error) {
+	seg, err := w.readHeader()
+	if err != nil {
+		return 0, err
+	}
+	switch seg[0] {
+	case RespArray, RespSet, RespPush:
+		if isStreamed(seg) {
+			return 0, w.unexpected(seg, "sized array")
+		}
+		n, err := bulkLen(seg)
+		return int(n), err
+	default:
+		return 0, w.unexpected(seg, "array")
+	}
+}
+
+// ReadMapLen reads the header of a resp3 map, or of the flat key/value
+// array replied in resp2, and returns the number of pairs; the caller must
+// then read every key and value.
+func (w *RESP) ReadMapLen() (int, error) {
+	seg, err := w.readHeader()
+	if err != nil {
+		return 0, err
+	}
+	switch seg[0] {
+	case RespMap:
+		if isStreamed(seg) {
+			return 0, w.unexpected(seg, "sized map")
+		}
+		n, err := byteToInt(seg[1:])
+		return int(n), err
+	case RespArray:
+		n, err := bulkLen(seg)
+		if err != nil {
+			return 0, err
+		}
+		if n%2 != 0 {
+			for i := int64(0); i < n; i++ {
+				if _, err := w.readElem(); err != nil {
+					return 0, err
+				}
+			}
+			return 0, fmt.Errorf("redis: got %d elements in key/value reply, want even", n)
+		}
+		return int(n / 2), nil
+	default:
+		return 0, w.unexpected(seg, "map")
+	}
+}
+
+func boolToInt(bs []byte) (int64, error) {
+	switch string(bs) {
+	case "t":
+		return 1, nil
+	case "f":
+		return 0, nil
+	}
+	return 0, UnexpectedData
+}
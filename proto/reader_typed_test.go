@@ -0,0 +1,195 @@
+package proto
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newTestReader(s string) *RESP {
+	return NewReader(bufio.NewReader(strings.NewReader(s)))
+}
+
+func TestTypedReaders(t *testing.T) {
+	rd := newTestReader("+OK\r\n$5\r\nhello\r\n=8\r\ntxt:info\r\n:42\r\n$2\r\n-7\r\n#t\r\n,2.5\r\n$3\r\n1.5\r\n")
+	for _, want := range []string{"OK", "hello", "info"} {
+		if got, err := rd.ReadString(); err != nil || got != want {
+			t.Fatalf("ReadString: got %q, %v, want %q", got, err, want)
+		}
+	}
+	for _, want := range []int64{42, -7, 1} {
+		if got, err := rd.ReadInt(); err != nil || got != want {
+			t.Fatalf("ReadInt: got %d, %v, want %d", got, err, want)
+		}
+	}
+	for _, want := range []float64{2.5, 1.5} {
+		if got, err := rd.ReadFloat(); err != nil || got != want {
+			t.Fatalf("ReadFloat: got %v, %v, want %v", got, err, want)
+		}
+	}
+}
+
+func TestTypedReadersNilAndErrors(t *testing.T) {
+	rd := newTestReader("$-1\r\n_\r\n-WRONGTYPE Operation against a key\r\n*-1\r\n")
+	if _, err := rd.ReadString(); err != Nil {
+		t.Fatalf("got %v, want Nil", err)
+	}
+	if _, err := rd.ReadBytes(); err != Nil {
+		t.Fatalf("got %v, want Nil", err)
+	}
+	if _, err := rd.ReadInt(); !errors.Is(err, ErrWrongType) {
+		t.Fatalf("got %v, want WRONGTYPE", err)
+	}
+	if _, err := rd.ReadArrayLen(); err != Nil {
+		t.Fatalf("got %v, want Nil", err)
+	}
+}
+
+func TestTypedReadersConsumeUnexpected(t *testing.T) {
+	rd := newTestReader("*2\r\n$1\r\na\r\n$1\r\nb\r\n+OK\r\n")
+	if _, err := rd.ReadString(); err == nil {
+		t.Fatal("got nil error for an array read as string")
+	}
+	if got, err := rd.ReadString(); err != nil || got != "OK" {
+		t.Fatalf("got %q, %v after unexpected reply", got, err)
+	}
+}
+
+func TestReadMapLen(t *testing.T) {
+	for _, in := range []string{"%2\r\n", "*4\r\n"} {
+		n, err := newTestReader(in).ReadMapLen()
+		if err != nil || n != 2 {
+			t.Fatalf("%q: got %d, %v, want 2", in, n, err)
+		}
+	}
+}
+
+var bulkReply = []byte("$64\r\n" + strings.Repeat("v", 64) + "\r\n")
+
+func benchmarkRead(b *testing.B, reply []byte, read func(*RESP) error) {
+	br := bytes.NewReader(reply)
+	rd := bufio.NewReader(br)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		br.Reset(reply)
+		rd.Reset(br)
+		w := AcquireReader(rd)
+		err := read(w)
+		Release(w)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReadBulk compares the generic interface{} reader, as used
+// before the typed readers, with ReadString and ReadBytes.
+func BenchmarkReadBulk(b *testing.B) {
+	b.Run("Read", func(b *testing.B) {
+		benchmarkRead(b, bulkReply, func(w *RESP) error {
+			_, err := NewReader(w.Reader).Read()
+			return err
+		})
+	})
+	b.Run("ReadString", func(b *testing.B) {
+		benchmarkRead(b, bulkReply, func(w *RESP) error {
+			_, err := w.ReadString()
+			return err
+		})
+	})
+	b.Run("ReadBytes", func(b *testing.B) {
+		benchmarkRead(b, bulkReply, func(w *RESP) error {
+			_, err := w.ReadBytes()
+			return err
+		})
+	})
+}
+
+func BenchmarkReadInt(b *testing.B) {
+	reply := []byte(":1234567\r\n")
+	b.Run("Read", func(b *testing.B) {
+		benchmarkRead(b, reply, func(w *RESP) error {
+			_, err := NewReader(w.Reader).Read()
+			return err
+		})
+	})
+	b.Run("ReadInt", func(b *testing.B) {
+		benchmarkRead(b, reply, func(w *RESP) error {
+			_, err := w.ReadInt()
+			return err
+		})
+	})
+}
+
+func BenchmarkReadStatus(b *testing.B) {
+	reply := []byte("+OK\r\n")
+	b.Run("Read", func(b *testing.B) {
+		benchmarkRead(b, reply, func(w *RESP) error {
+			_, err := NewReader(w.Reader).Read()
+			return err
+		})
+	})
+	b.Run("ReadString", func(b *testing.B) {
+		benchmarkRead(b, reply, func(w *RESP) error {
+			_, err := w.ReadString()
+			return err
+		})
+	})
+}
+
+func BenchmarkWrite(b *testing.B) {
+	ctx := context.Background()
+	args := []interface{}{"SET", "key", strings.Repeat("v", 64), "px", int64(1500)}
+	wd := bufio.NewWriter(io.Discard)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := AcquireWriter(wd)
+		if err := w.Write(ctx, args); err != nil {
+			b.Fatal(err)
+		}
+		Release(w)
+	}
+}
+
+func TestBulkStreaming(t *testing.T) {
+	payload := bytes.Repeat([]byte("0123456789"), 100000)
+
+	var req bytes.Buffer
+	wd := bufio.NewWriterSize(&req, 16)
+	err := NewWriter(wd).Write(context.Background(), []interface{}{
+		"SET", "k", &BulkReader{R: bytes.NewReader(payload), Size: int64(len(payload))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = wd.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$1000000\r\n"; !bytes.HasPrefix(req.Bytes(), []byte(want)) {
+		t.Fatalf("got request prefix %q", req.Bytes()[:40])
+	}
+
+	reply := append([]byte("$1000000\r\n"), payload...)
+	reply = append(reply, "\r\n+OK\r\n"...)
+	rd := NewReader(bufio.NewReaderSize(bytes.NewReader(reply), 16))
+
+	var got bytes.Buffer
+	n, err := rd.ReadBulkTo(&got)
+	if err != nil || n != int64(len(payload)) || !bytes.Equal(got.Bytes(), payload) {
+		t.Fatalf("got %d bytes, %v", n, err)
+	}
+	if s, err := rd.ReadString(); err != nil || s != "OK" {
+		t.Fatalf("got %q, %v after the bulk", s, err)
+	}
+
+	short := &BulkReader{R: bytes.NewReader(payload[:10]), Size: 20}
+	if err := NewWriter(bufio.NewWriter(io.Discard)).Write(context.Background(), []interface{}{short}); err == nil {
+		t.Fatal("got nil error for a short bulk reader")
+	}
+}
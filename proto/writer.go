@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding"
 	"fmt"
+	"io"
 	"net"
 	"strconv"
 	"time"
@@ -11,8 +12,7 @@ import (
 
 func (w *RESP) Write(ctx context.Context, args []interface{}) error {
 	// *n
-	bs := append(strconv.AppendUint([]byte{RespArray}, uint64(len(args)), 10), '\r', '\n')
-	if _, err := w.Writer.Write(bs); err != nil {
+	if err := w.writeHeader(RespArray, int64(len(args))); err != nil {
 		return err
 	}
 
@@ -65,7 +65,7 @@ func (w *RESP) writeArg(v interface{}) error {
 		}
 		return w.writeInt(bi)
 	case time.Time:
-		return w.writeBytes(v.AppendFormat([]byte{}, time.RFC3339Nano))
+		return w.writeBytes(v.AppendFormat(w.argBuf[:0], time.RFC3339Nano))
 	case time.Duration:
 		return w.writeInt(v.Nanoseconds())
 	case encoding.BinaryMarshaler:
@@ -76,37 +76,75 @@ func (w *RESP) writeArg(v interface{}) error {
 		return w.writeBytes(b)
 	case net.IP:
 		return w.writeBytes(v)
+	case *BulkReader:
+		return w.writeBulkReader(v)
+	case encoding.TextMarshaler:
+		b, err := v.MarshalText()
+		if err != nil {
+			return err
+		}
+		return w.writeBytes(b)
+	case fmt.Stringer:
+		return w.writeString(v.String())
 	default:
 		return fmt.Errorf("redis: can't marshal %T (implement encoding.BinaryMarshaler)", v)
 	}
 }
 
-func (w *RESP) writeBytes(b []byte) error {
-	bs := strconv.AppendInt([]byte{RespString}, int64(len(b)), 10)
-	bs = append(bs, '\r', '\n')
-	bs = append(bs, b...)
+// writeHeader writes <typ><n>\r\n, formatted in the lenBuf scratch space.
+func (w *RESP) writeHeader(typ byte, n int64) error {
+	bs := append(w.lenBuf[:0], typ)
+	bs = strconv.AppendInt(bs, n, 10)
 	bs = append(bs, '\r', '\n')
-	if _, err := w.Writer.Write(bs); err != nil {
+	_, err := w.Writer.Write(bs)
+	return err
+}
+
+func (w *RESP) writeBytes(b []byte) error {
+	if err := w.writeHeader(RespString, int64(len(b))); err != nil {
 		return err
 	}
-	return nil
+	if _, err := w.Writer.Write(b); err != nil {
+		return err
+	}
+	_, err := w.Writer.WriteString("\r\n")
+	return err
 }
 
 func (w *RESP) writeString(s string) error {
-	return w.writeBytes([]byte(s))
+	if err := w.writeHeader(RespString, int64(len(s))); err != nil {
+		return err
+	}
+	if _, err := w.Writer.WriteString(s); err != nil {
+		return err
+	}
+	_, err := w.Writer.WriteString("\r\n")
+	return err
+}
+
+func (w *RESP) writeBulkReader(br *BulkReader) error {
+	if err := w.writeHeader(RespString, br.Size); err != nil {
+		return err
+	}
+	n, err := io.CopyN(w.Writer, br.R, br.Size)
+	if err != nil {
+		if err == io.EOF {
+			return fmt.Errorf("redis: bulk reader ended after %d of %d bytes", n, br.Size)
+		}
+		return err
+	}
+	_, err = w.Writer.WriteString("\r\n")
+	return err
 }
 
 func (w *RESP) writeUint(n uint64) error {
-	bs := strconv.AppendUint([]byte{}, n, 10)
-	return w.writeBytes(bs)
+	return w.writeBytes(strconv.AppendUint(w.argBuf[:0], n, 10))
 }
 
 func (w *RESP) writeInt(n int64) error {
-	bs := strconv.AppendInt([]byte{}, n, 10)
-	return w.writeBytes(bs)
+	return w.writeBytes(strconv.AppendInt(w.argBuf[:0], n, 10))
 }
 
 func (w *RESP) writeFloat(f float64) error {
-	bs := strconv.AppendFloat([]byte{}, f, 'f', -1, 64)
-	return w.writeBytes(bs)
+	return w.writeBytes(strconv.AppendFloat(w.argBuf[:0], f, 'f', -1, 64))
 }
@@ -0,0 +1,351 @@
+package go_redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mingolm/go-redis/pool"
+	"github.com/mingolm/go-redis/proto"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by the methods of a closed PubSub.
+var ErrClosed = errors.New("redis: closed")
+
+// Message is a message received on a subscribed channel. Pattern is the
+// matching pattern for messages of pattern subscriptions.
+type Message struct {
+	Channel string
+	Pattern string
+	Payload string
+}
+
+func (m *Message) String() string {
+	if m.Pattern != "" {
+		return fmt.Sprintf("Message<%s: %s: %s>", m.Pattern, m.Channel, m.Payload)
+	}
+	return fmt.Sprintf("Message<%s: %s>", m.Channel, m.Payload)
+}
+
+// PubSub receives messages on a dedicated connection, outside the pool.
+// When the connection is lost it reconnects and subscribes again to the
+// same channels; messages published in between are lost.
+//
+//	ps, err := redis.Subscribe(ctx, "events")
+//	if err != nil {
+//	}
+//	defer ps.Close()
+//	for msg := range ps.Channel() {
+//	}
+type PubSub struct {
+	r *Redis
+
+	mu       sync.Mutex // 保护 cn 及订阅列表, 串行写入
+	cn       *pool.Conn
+	channels map[string]struct{}
+	patterns map[string]struct{}
+	closed   bool
+	// onReconnect is called once resubscribed after a reconnection
+	onReconnect func()
+
+	msgCh chan *Message
+	done  chan struct{}
+}
+
+// Subscribe connects and subscribes to channels.
+func (r *Redis) Subscribe(ctx context.Context, channels ...string) (*PubSub, error) {
+	return r.newPubSub(ctx, channels, nil)
+}
+
+// PSubscribe connects and subscribes to the channels matching patterns.
+func (r *Redis) PSubscribe(ctx context.Context, patterns ...string) (*PubSub, error) {
+	return r.newPubSub(ctx, nil, patterns)
+}
+
+func (r *Redis) newPubSub(ctx context.Context, channels, patterns []string) (*PubSub, error) {
+	ps := &PubSub{
+		r:        r,
+		channels: make(map[string]struct{}),
+		patterns: make(map[string]struct{}),
+		msgCh:    make(chan *Message, 100),
+		done:     make(chan struct{}),
+	}
+	for _, channel := range channels {
+		ps.channels[channel] = struct{}{}
+	}
+	for _, pattern := range patterns {
+		ps.patterns[pattern] = struct{}{}
+	}
+
+	if err := ps.connect(ctx); err != nil {
+		return nil, err
+	}
+	go ps.run()
+	return ps, nil
+}
+
+// newConn dials a connection set up like the pooled ones.
+func (r *Redis) newConn(ctx context.Context) (*pool.Conn, error) {
+	netConn, err := r.opt.Dialer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cn := pool.NewConnect(netConn)
+	if err := r.initConn(ctx, cn); err != nil {
+		_ = cn.Close()
+		return nil, err
+	}
+	return cn, nil
+}
+
+// connect replaces the connection and subscribes to the current channels.
+func (ps *PubSub) connect(ctx context.Context) error {
+	cn, err := ps.r.newConn(ctx)
+	if err != nil {
+		return err
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.closed {
+		_ = cn.Close()
+		return ErrClosed
+	}
+	ps.cn = cn
+	err = ps.write(ctx, "SUBSCRIBE", keysOf(ps.channels))
+	if err == nil {
+		err = ps.write(ctx, "PSUBSCRIBE", keysOf(ps.patterns))
+	}
+	if err != nil {
+		_ = cn.Close()
+	}
+	return err
+}
+
+func keysOf(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// write sends a (un)subscription command, without waiting for its
+// confirmation which is read by run. ps.mu must be held.
+func (ps *PubSub) write(ctx context.Context, name string, args []string) error {
+	if len(args) == 0 && (name == "SUBSCRIBE" || name == "PSUBSCRIBE") {
+		return nil
+	}
+	return ps.cn.WithWrite(ctx, func(ctx context.Context, wd *bufio.Writer) error {
+		writer := proto.AcquireWriter(wd)
+		defer proto.Release(writer)
+		return writer.Write(ctx, keysArgs(name, args))
+	})
+}
+
+// OnReconnect sets fn to be called after each reconnection, once the
+// server confirmed the subscriptions again. Messages published while
+// disconnected are lost, fn may resync what they would have updated.
+func (ps *PubSub) OnReconnect(fn func()) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.onReconnect = fn
+}
+
+func (ps *PubSub) Subscribe(ctx context.Context, channels ...string) error {
+	return ps.update(ctx, "SUBSCRIBE", ps.channels, channels, true)
+}
+
+// Unsubscribe unsubscribes from channels, or from all channels if none
+// is given.
+func (ps *PubSub) Unsubscribe(ctx context.Context, channels ...string) error {
+	return ps.update(ctx, "UNSUBSCRIBE", ps.channels, channels, false)
+}
+
+func (ps *PubSub) PSubscribe(ctx context.Context, patterns ...string) error {
+	return ps.update(ctx, "PSUBSCRIBE", ps.patterns, patterns, true)
+}
+
+// PUnsubscribe unsubscribes from patterns, or from all patterns if none
+// is given.
+func (ps *PubSub) PUnsubscribe(ctx context.Context, patterns ...string) error {
+	return ps.update(ctx, "PUNSUBSCRIBE", ps.patterns, patterns, false)
+}
+
+func (ps *PubSub) update(ctx context.Context, name string, set map[string]struct{}, names []string, add bool) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.closed {
+		return ErrClosed
+	}
+
+	switch {
+	case add:
+		for _, n := range names {
+			set[n] = struct{}{}
+		}
+	case len(names) == 0:
+		clear(set)
+	default:
+		for _, n := range names {
+			delete(set, n)
+		}
+	}
+	// on failure run reconnects with the updated set
+	return ps.write(ctx, name, names)
+}
+
+// Channel returns the channel of received messages, closed by Close.
+// Receiving stops while the channel is full.
+func (ps *PubSub) Channel() <-chan *Message {
+	return ps.msgCh
+}
+
+func (ps *PubSub) Close() error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.closed {
+		return nil
+	}
+	ps.closed = true
+	close(ps.done)
+	// unblocks run, the connection may have been closed by it already
+	_ = ps.cn.Close()
+	return nil
+}
+
+func (ps *PubSub) isClosed() bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return ps.closed
+}
+
+func (ps *PubSub) conn() *pool.Conn {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return ps.cn
+}
+
+// run reads the connection until Close, reconnecting on errors.
+func (ps *PubSub) run() {
+	defer close(ps.msgCh)
+
+	var (
+		ctx         = context.Background()
+		reconnected bool
+	)
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ps.done:
+				return
+			default:
+			}
+			if err := ps.connect(ctx); err != nil {
+				if errors.Is(err, ErrClosed) {
+					return
+				}
+				ps.r.opt.Logger.Errorw("pubsub reconnect failed",
+					"err", err,
+				)
+				t := time.NewTimer(retryBackoff(attempt))
+				select {
+				case <-ps.done:
+					t.Stop()
+					return
+				case <-t.C:
+				}
+				continue
+			}
+			attempt = 0
+			reconnected = true
+		}
+
+		cn := ps.conn()
+		err := cn.WithRead(ctx, func(ctx context.Context, rd *bufio.Reader) error {
+			reader := proto.NewReader(rd)
+			for {
+				val, err := reader.Read()
+				if err != nil {
+					if proto.IsRedisError(err) {
+						ps.r.opt.Logger.Errorw("pubsub command failed",
+							"err", err,
+						)
+						continue
+					}
+					return err
+				}
+				if reconnected && isSubscription(val) {
+					reconnected = false
+					ps.reconnect()
+				}
+				if !ps.dispatch(val) {
+					return ErrClosed
+				}
+			}
+		})
+		_ = cn.Close()
+		if ps.isClosed() {
+			return
+		}
+		ps.r.opt.Logger.Debugw("pubsub connection lost",
+			"err", err,
+		)
+	}
+}
+
+func (ps *PubSub) reconnect() {
+	ps.mu.Lock()
+	fn := ps.onReconnect
+	ps.mu.Unlock()
+	if fn != nil {
+		fn()
+	}
+}
+
+// isSubscription reports the confirmation of a subscription.
+func isSubscription(val interface{}) bool {
+	vals, ok := val.([]interface{})
+	if !ok || len(vals) == 0 {
+		return false
+	}
+	kind, _ := vals[0].(string)
+	return kind == "subscribe" || kind == "psubscribe"
+}
+
+// dispatch delivers a message push, ignoring the confirmations of
+// (un)subscriptions. It reports false once closed.
+func (ps *PubSub) dispatch(val interface{}) bool {
+	vals, ok := val.([]interface{})
+	if !ok || len(vals) < 3 {
+		return true
+	}
+	kind, _ := vals[0].(string)
+
+	var msg *Message
+	switch kind {
+	case "message":
+		msg = &Message{}
+		msg.Channel, _ = toString(vals[1])
+		msg.Payload, _ = toString(vals[2])
+	case "pmessage":
+		if len(vals) < 4 {
+			return true
+		}
+		msg = &Message{}
+		msg.Pattern, _ = toString(vals[1])
+		msg.Channel, _ = toString(vals[2])
+		msg.Payload, _ = toString(vals[3])
+	default:
+		return true
+	}
+
+	select {
+	case ps.msgCh <- msg:
+		return true
+	case <-ps.done:
+		return false
+	}
+}
@@ -0,0 +1,27 @@
+package go_redis
+
+import "context"
+
+type PubSubCmdable interface {
+	Publish(ctx context.Context, channel string, message interface{}) *IntCmd
+	PubSubChannels(ctx context.Context, pattern string) *StringSliceCmd
+}
+
+// Publish returns the number of clients that received the message.
+func (c cmdable) Publish(ctx context.Context, channel string, message interface{}) *IntCmd {
+	cmd := newIntCmd(ctx, "PUBLISH", channel, message)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// PubSubChannels lists the channels with subscribers, matching pattern
+// unless empty.
+func (c cmdable) PubSubChannels(ctx context.Context, pattern string) *StringSliceCmd {
+	args := []interface{}{"PUBSUB", "CHANNELS"}
+	if pattern != "" {
+		args = append(args, pattern)
+	}
+	cmd := newStringSliceCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
@@ -0,0 +1,122 @@
+package go_redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/mingolm/go-redis/proto"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer dials in-memory connections served by handle, which gets the
+// command arguments and writes raw RESP to the connection.
+func fakeServer(handle func(cn net.Conn, args []string)) func(ctx context.Context) (net.Conn, error) {
+	return func(ctx context.Context) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			rd := proto.NewReader(bufio.NewReader(server))
+			for {
+				val, err := rd.Read()
+				if err != nil {
+					return
+				}
+				var args []string
+				for _, v := range val.([]interface{}) {
+					args = append(args, v.(string))
+				}
+				handle(server, args)
+			}
+		}()
+		return client, nil
+	}
+}
+
+func TestPubSub(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		subs  []net.Conn
+		subCh = make(chan struct{}, 10)
+	)
+	dialer := fakeServer(func(cn net.Conn, args []string) {
+		switch args[0] {
+		case "HELLO":
+			fmt.Fprint(cn, "-ERR unknown command 'HELLO'\r\n")
+		case "SUBSCRIBE":
+			for i, ch := range args[1:] {
+				fmt.Fprintf(cn, "*3\r\n$9\r\nsubscribe\r\n$%d\r\n%s\r\n:%d\r\n", len(ch), ch, i+1)
+			}
+			mu.Lock()
+			subs = append(subs, cn)
+			mu.Unlock()
+			subCh <- struct{}{}
+		default:
+			fmt.Fprint(cn, "+OK\r\n")
+		}
+	})
+	publish := func(channel, payload string) {
+		mu.Lock()
+		cn := subs[len(subs)-1]
+		mu.Unlock()
+		fmt.Fprintf(cn, "*3\r\n$7\r\nmessage\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(channel), channel, len(payload), payload)
+	}
+
+	r := NewClient(&Options{Dialer: dialer, PoolSize: 2, MinIdleConns: 1, MaxIdleConns: 1})
+	sub, err := r.Subscribe(context.Background(), "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	<-subCh
+	reconnects := make(chan struct{}, 1)
+	sub.OnReconnect(func() {
+		reconnects <- struct{}{}
+	})
+
+	recv := func() *Message {
+		select {
+		case msg := <-sub.Channel():
+			return msg
+		case <-time.After(time.Second):
+			t.Fatal("no message received")
+			return nil
+		}
+	}
+
+	publish("events", "hello")
+	if msg := recv(); msg.Channel != "events" || msg.Payload != "hello" {
+		t.Fatalf("got %v", msg)
+	}
+
+	// the subscription survives the loss of the connection
+	mu.Lock()
+	subs[0].Close()
+	mu.Unlock()
+	select {
+	case <-subCh:
+	case <-time.After(time.Second):
+		t.Fatal("not resubscribed")
+	}
+	select {
+	case <-reconnects:
+	case <-time.After(time.Second):
+		t.Fatal("reconnection not notified")
+	}
+	publish("events", "again")
+	if msg := recv(); msg.Payload != "again" {
+		t.Fatalf("got %v", msg)
+	}
+
+	if err := sub.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-sub.Channel(); ok {
+		t.Fatal("channel not closed")
+	}
+	if err := sub.Subscribe(context.Background(), "more"); err != ErrClosed {
+		t.Fatalf("got %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,90 @@
+package queue
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Priority selects the list a message is enqueued to. Dequeue serves
+// High first, then Normal, then Low.
+type Priority int
+
+// The values are stored in the messages, don't reorder.
+const (
+	Normal Priority = iota
+	High
+	Low
+)
+
+// priorities is the order in which the lists are served.
+var priorities = []Priority{High, Normal, Low}
+
+func (p Priority) String() string {
+	switch p {
+	case Normal:
+		return "normal"
+	case High:
+		return "high"
+	case Low:
+		return "low"
+	default:
+		return fmt.Sprintf("priority(%d)", int(p))
+	}
+}
+
+func (p Priority) valid() bool {
+	return p >= Normal && p <= Low
+}
+
+// Message is a queued message. It is stored as JSON, whose encoding
+// identifies it in the lists, so it is never modified once enqueued.
+type Message struct {
+	ID         string    `json:"id"`
+	Body       []byte    `json:"body"`
+	Priority   Priority  `json:"priority"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+
+	raw    string
+	worker string
+}
+
+// Job is a message to enqueue with EnqueueBatch.
+type Job struct {
+	Body     []byte
+	Priority Priority
+}
+
+func newMessage(body []byte, prio Priority) (*Message, error) {
+	if !prio.valid() {
+		return nil, fmt.Errorf("queue: invalid priority %d", int(prio))
+	}
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	msg := &Message{
+		ID:         hex.EncodeToString(b[:]),
+		Body:       body,
+		Priority:   prio,
+		EnqueuedAt: time.Now(),
+	}
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	msg.raw = string(raw)
+	return msg, nil
+}
+
+func decodeMessage(raw, worker string) (*Message, error) {
+	msg := &Message{}
+	if err := json.Unmarshal([]byte(raw), msg); err != nil {
+		return nil, fmt.Errorf("queue: invalid message %q: %w", raw, err)
+	}
+	msg.raw = raw
+	msg.worker = worker
+	return msg, nil
+}
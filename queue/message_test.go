@@ -0,0 +1,53 @@
+package queue
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMessage(t *testing.T) {
+	msg, err := newMessage([]byte("hello"), High)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := newMessage([]byte("hello"), High)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the encoding identifies the message in the lists
+	if msg.ID == other.ID || msg.raw == other.raw {
+		t.Fatal("same body encoded the same")
+	}
+
+	got, err := decodeMessage(msg.raw, "w1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != msg.ID || !bytes.Equal(got.Body, msg.Body) || got.Priority != High ||
+		!got.EnqueuedAt.Equal(msg.EnqueuedAt) {
+		t.Fatalf("got %+v, want %+v", got, msg)
+	}
+	if got.raw != msg.raw || got.worker != "w1" {
+		t.Fatalf("got raw %q of worker %q", got.raw, got.worker)
+	}
+
+	if _, err := newMessage(nil, Low+1); err == nil {
+		t.Fatal("invalid priority accepted")
+	}
+	if _, err := decodeMessage("not json", "w1"); err == nil {
+		t.Fatal("invalid message decoded")
+	}
+}
+
+func TestKeys(t *testing.T) {
+	q := New(nil, "jobs", nil)
+	if got := q.pendingKey(High); got != "queue:jobs:high" {
+		t.Fatalf("got %q", got)
+	}
+	if got := q.processingKey("w1"); got != "queue:jobs:processing:w1" {
+		t.Fatalf("got %q", got)
+	}
+	if q.opt.JanitorInterval != q.opt.VisibilityTimeout/3 {
+		t.Fatalf("got janitor interval %v", q.opt.JanitorInterval)
+	}
+}
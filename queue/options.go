@@ -0,0 +1,36 @@
+package queue
+
+import "time"
+
+type Options struct {
+	// VisibilityTimeout is how long a dequeued message may stay unacked
+	// before the janitor delivers it again.
+	// Default is 30 seconds.
+	VisibilityTimeout time.Duration
+
+	// PollInterval bounds how long Dequeue blocks on the High list while
+	// all lists are empty, which is also how late it may notice a message
+	// of lower priority. It is capped at half of VisibilityTimeout, since
+	// a worker is forgotten by the janitor when idle for longer than that.
+	// Default is 1 second.
+	PollInterval time.Duration
+
+	// JanitorInterval is the period of the passes of RunJanitor.
+	// Default is a third of VisibilityTimeout.
+	JanitorInterval time.Duration
+}
+
+func (opt *Options) init() {
+	if opt.VisibilityTimeout <= 0 {
+		opt.VisibilityTimeout = 30 * time.Second
+	}
+	if opt.PollInterval <= 0 {
+		opt.PollInterval = time.Second
+	}
+	if opt.PollInterval > opt.VisibilityTimeout/2 {
+		opt.PollInterval = opt.VisibilityTimeout / 2
+	}
+	if opt.JanitorInterval <= 0 {
+		opt.JanitorInterval = opt.VisibilityTimeout / 3
+	}
+}
@@ -0,0 +1,293 @@
+// Package queue implements a reliable work queue on Redis lists: a
+// dequeued message is moved to a list of the worker until acked, and
+// delivered again if the worker doesn't ack it within the visibility
+// timeout, e.g. because it crashed:
+//
+//	q := queue.New(redis, "emails", &queue.Options{VisibilityTimeout: time.Minute})
+//	go q.RunJanitor(ctx)
+//
+//	_, err := q.Enqueue(ctx, body, queue.Normal)
+//
+//	// in a worker
+//	msg, err := q.Dequeue(ctx, hostname, 5*time.Second)
+//	if errors.Is(err, queue.ErrNoMessage) {
+//	}
+//	send(msg.Body)
+//	err = q.Ack(ctx, msg)
+//
+// Delivery is at least once: a message may be processed again when its
+// worker was too slow to ack it.
+package queue
+
+import (
+	"context"
+	"errors"
+	go_redis "github.com/mingolm/go-redis"
+	"github.com/mingolm/go-redis/proto"
+	"time"
+)
+
+var (
+	// ErrNoMessage is returned by Dequeue when no message arrived in time.
+	ErrNoMessage = errors.New("queue: no message")
+	// ErrRequeued is returned by Ack when the message is no longer held by
+	// its worker: the visibility timeout elapsed and it was requeued.
+	ErrRequeued = errors.New("queue: message was requeued")
+)
+
+// nowLua sets now to the time of the server in milliseconds, so that
+// deadlines don't depend on the clocks of the clients.
+const nowLua = `
+local t = redis.call("TIME")
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+`
+
+// claimScript registers the worker and moves the oldest message of the
+// first non-empty list to its processing list.
+// KEYS: processing, deadlines, workers, pending lists by priority;
+// ARGV: visibility ms, worker.
+var claimScript = go_redis.NewScript(nowLua + `
+redis.call("ZADD", KEYS[3], now, ARGV[2])
+for i = 4, #KEYS do
+	local raw = redis.call("LMOVE", KEYS[i], KEYS[1], "RIGHT", "LEFT")
+	if raw then
+		redis.call("ZADD", KEYS[2], now + tonumber(ARGV[1]), raw)
+		return raw
+	end
+end
+return false
+`)
+
+// hideScript sets the deadline of a message moved by BLMOVE, unless the
+// janitor did in between, and registers the worker again, as the janitor
+// may have forgotten it while it was blocked.
+// KEYS: deadlines, workers; ARGV: visibility ms, message, worker.
+var hideScript = go_redis.NewScript(nowLua + `
+redis.call("ZADD", KEYS[2], now, ARGV[3])
+return redis.call("ZADD", KEYS[1], "NX", now + tonumber(ARGV[1]), ARGV[2])
+`)
+
+// ackScript leaves the deadline alone if the message was requeued, as it
+// may be held by another worker by now.
+var ackScript = go_redis.NewScript(`
+local n = redis.call("LREM", KEYS[1], -1, ARGV[1])
+if n > 0 then
+	redis.call("ZREM", KEYS[2], ARGV[1])
+end
+return n
+`)
+
+// requeueScript puts the messages of a worker past their deadline back
+// at the head of their list. A message without a deadline, moved by a
+// worker that stopped before setting it, gets one. The worker is
+// forgotten once idle for the visibility timeout.
+// KEYS: processing, deadlines, workers, Normal, High and Low lists;
+// ARGV: visibility ms, worker.
+var requeueScript = go_redis.NewScript(nowLua + `
+local vis = tonumber(ARGV[1])
+local n = 0
+for _, raw in ipairs(redis.call("LRANGE", KEYS[1], 0, -1)) do
+	local deadline = redis.call("ZSCORE", KEYS[2], raw)
+	if not deadline then
+		redis.call("ZADD", KEYS[2], now + vis, raw)
+	elseif tonumber(deadline) <= now then
+		local prio = 0
+		local ok, msg = pcall(cjson.decode, raw)
+		if ok and type(msg) == "table" then
+			prio = tonumber(msg["priority"]) or 0
+		end
+		if prio < 0 or prio > 2 then
+			prio = 0
+		end
+		redis.call("LREM", KEYS[1], -1, raw)
+		redis.call("ZREM", KEYS[2], raw)
+		redis.call("RPUSH", KEYS[4 + prio], raw)
+		n = n + 1
+	end
+end
+if redis.call("LLEN", KEYS[1]) == 0 then
+	local seen = redis.call("ZSCORE", KEYS[3], ARGV[2])
+	if not seen or tonumber(seen) + vis <= now then
+		redis.call("ZREM", KEYS[3], ARGV[2])
+	end
+end
+return n
+`)
+
+type Queue struct {
+	rdb  *go_redis.Redis
+	name string
+	opt  *Options
+}
+
+func New(rdb *go_redis.Redis, name string, opt *Options) *Queue {
+	if opt == nil {
+		opt = &Options{}
+	}
+	opt.init()
+	return &Queue{
+		rdb:  rdb,
+		name: name,
+		opt:  opt,
+	}
+}
+
+func (q *Queue) pendingKey(prio Priority) string {
+	return "queue:" + q.name + ":" + prio.String()
+}
+
+func (q *Queue) processingKey(worker string) string {
+	return "queue:" + q.name + ":processing:" + worker
+}
+
+func (q *Queue) deadlinesKey() string {
+	return "queue:" + q.name + ":deadlines"
+}
+
+func (q *Queue) workersKey() string {
+	return "queue:" + q.name + ":workers"
+}
+
+// Enqueue adds a message with body to the list of prio.
+func (q *Queue) Enqueue(ctx context.Context, body []byte, prio Priority) (*Message, error) {
+	msg, err := newMessage(body, prio)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.rdb.LPush(ctx, q.pendingKey(prio), msg.raw).Err(); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// EnqueueBatch adds a message per job in a single round trip, keeping
+// their order within each priority. The messages of a priority are
+// enqueued all or none, but a failure may leave other priorities
+// enqueued.
+func (q *Queue) EnqueueBatch(ctx context.Context, jobs []Job) ([]*Message, error) {
+	msgs := make([]*Message, len(jobs))
+	values := make(map[Priority][]interface{})
+	for i, job := range jobs {
+		msg, err := newMessage(job.Body, job.Priority)
+		if err != nil {
+			return nil, err
+		}
+		msgs[i] = msg
+		values[job.Priority] = append(values[job.Priority], msg.raw)
+	}
+
+	_, err := q.rdb.Pipelined(ctx, func(p *go_redis.Pipeline) error {
+		for _, prio := range priorities {
+			if len(values[prio]) > 0 {
+				p.LPush(ctx, q.pendingKey(prio), values[prio]...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// Dequeue moves the next message to the processing list of worker, for
+// up to the visibility timeout, waiting up to timeout for one to arrive.
+// Worker names must be unique among the live workers of the queue. It
+// returns ErrNoMessage on timeout.
+func (q *Queue) Dequeue(ctx context.Context, worker string, timeout time.Duration) (*Message, error) {
+	var (
+		deadline   = time.Now().Add(timeout)
+		visibility = q.opt.VisibilityTimeout.Milliseconds()
+		keys       = []string{q.processingKey(worker), q.deadlinesKey(), q.workersKey()}
+	)
+	for _, prio := range priorities {
+		keys = append(keys, q.pendingKey(prio))
+	}
+
+	for {
+		raw, err := claimScript.Run(ctx, q.rdb, keys, visibility, worker).Text()
+		if err == nil {
+			return decodeMessage(raw, worker)
+		}
+		if !errors.Is(err, proto.Nil) {
+			return nil, err
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return nil, ErrNoMessage
+		}
+		if wait > q.opt.PollInterval {
+			wait = q.opt.PollInterval
+		}
+		// a 0 timeout would block forever
+		if wait < time.Millisecond {
+			wait = time.Millisecond
+		}
+		raw, err = q.rdb.BLMove(ctx, q.pendingKey(High), q.processingKey(worker), go_redis.Right, go_redis.Left, wait).Result()
+		if errors.Is(err, proto.Nil) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := hideScript.Run(ctx, q.rdb, []string{q.deadlinesKey(), q.workersKey()}, visibility, raw, worker).Err(); err != nil {
+			return nil, err
+		}
+		return decodeMessage(raw, worker)
+	}
+}
+
+// Ack removes a processed message. It returns ErrRequeued if the message
+// was requeued meanwhile, to be delivered again.
+func (q *Queue) Ack(ctx context.Context, msg *Message) error {
+	n, err := ackScript.Run(ctx, q.rdb, []string{q.processingKey(msg.worker), q.deadlinesKey()}, msg.raw).Int64()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrRequeued
+	}
+	return nil
+}
+
+// Requeue makes a single pass of the janitor, requeueing the messages of
+// every worker held past the visibility timeout, and returns how many.
+func (q *Queue) Requeue(ctx context.Context) (int, error) {
+	workers, err := q.rdb.ZRange(ctx, q.workersKey(), 0, -1).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int
+	for _, worker := range workers {
+		keys := []string{q.processingKey(worker), q.deadlinesKey(), q.workersKey(),
+			q.pendingKey(Normal), q.pendingKey(High), q.pendingKey(Low)}
+		n, err := requeueScript.Run(ctx, q.rdb, keys, q.opt.VisibilityTimeout.Milliseconds(), worker).Int64()
+		if err != nil {
+			return total, err
+		}
+		total += int(n)
+	}
+	return total, nil
+}
+
+// RunJanitor runs Requeue every JanitorInterval until ctx is done, and
+// returns ctx.Err(). A failed pass is retried on the next one. Running
+// it in several processes is harmless.
+func (q *Queue) RunJanitor(ctx context.Context) error {
+	t := time.NewTicker(q.opt.JanitorInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			_, _ = q.Requeue(ctx)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
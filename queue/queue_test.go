@@ -0,0 +1,300 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/mingolm/go-redis/internal/redistest"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis runs the lists, sorted sets and scripts of a queue in memory,
+// at the time now.
+type fakeRedis struct {
+	mu     sync.Mutex
+	now    int64               // ms
+	lists  map[string][]string // head first
+	zsets  map[string]map[string]int64
+	lpushs int
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{
+		now:   time.Now().UnixMilli(),
+		lists: make(map[string][]string),
+		zsets: make(map[string]map[string]int64),
+	}
+}
+
+func (f *fakeRedis) zadd(key, member string, score int64, nx bool) {
+	z := f.zsets[key]
+	if z == nil {
+		z = make(map[string]int64)
+		f.zsets[key] = z
+	}
+	if _, ok := z[member]; ok && nx {
+		return
+	}
+	z[member] = score
+}
+
+// move pops the tail of src and pushes it at the head of dst.
+func (f *fakeRedis) move(src, dst string) (string, bool) {
+	l := f.lists[src]
+	if len(l) == 0 {
+		return "", false
+	}
+	raw := l[len(l)-1]
+	f.lists[src] = l[:len(l)-1]
+	f.lists[dst] = append([]string{raw}, f.lists[dst]...)
+	return raw, true
+}
+
+// lrem removes the last occurrence of raw in key.
+func (f *fakeRedis) lrem(key, raw string) bool {
+	l := f.lists[key]
+	for i := len(l) - 1; i >= 0; i-- {
+		if l[i] == raw {
+			f.lists[key] = append(l[:i:i], l[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeRedis) handle(cn net.Conn, args []string) {
+	if args[0] == "BLMOVE" {
+		timeout, _ := strconv.ParseFloat(args[5], 64)
+		deadline := time.Now().Add(time.Duration(timeout * float64(time.Second)))
+		for {
+			f.mu.Lock()
+			raw, ok := f.move(args[1], args[2])
+			f.mu.Unlock()
+			if ok {
+				redistest.Bulk(cn, raw)
+				return
+			}
+			if time.Now().After(deadline) {
+				redistest.Nil(cn)
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch args[0] {
+	case "LPUSH":
+		f.lpushs++
+		for _, v := range args[2:] {
+			f.lists[args[1]] = append([]string{v}, f.lists[args[1]]...)
+		}
+		redistest.Int(cn, int64(len(f.lists[args[1]])))
+	case "ZRANGE":
+		var members []string
+		for m := range f.zsets[args[1]] {
+			members = append(members, m)
+		}
+		sort.Strings(members)
+		redistest.Strings(cn, members...)
+	case "EVALSHA":
+		n, _ := strconv.Atoi(args[2])
+		keys, argv := args[3:3+n], args[3+n:]
+		f.eval(cn, args[1], keys, argv)
+	default:
+		redistest.Error(cn, "ERR unknown command")
+	}
+}
+
+func (f *fakeRedis) eval(cn net.Conn, sha string, keys, argv []string) {
+	switch sha {
+	case claimScript.Hash():
+		vis, _ := strconv.ParseInt(argv[0], 10, 64)
+		f.zadd(keys[2], argv[1], f.now, false)
+		for _, pending := range keys[3:] {
+			if raw, ok := f.move(pending, keys[0]); ok {
+				f.zadd(keys[1], raw, f.now+vis, false)
+				redistest.Bulk(cn, raw)
+				return
+			}
+		}
+		redistest.Nil(cn)
+	case hideScript.Hash():
+		vis, _ := strconv.ParseInt(argv[0], 10, 64)
+		f.zadd(keys[1], argv[2], f.now, false)
+		f.zadd(keys[0], argv[1], f.now+vis, true)
+		redistest.Int(cn, 1)
+	case ackScript.Hash():
+		if !f.lrem(keys[0], argv[0]) {
+			redistest.Int(cn, 0)
+			return
+		}
+		delete(f.zsets[keys[1]], argv[0])
+		redistest.Int(cn, 1)
+	case requeueScript.Hash():
+		vis, _ := strconv.ParseInt(argv[0], 10, 64)
+		var n int64
+		for _, raw := range slices.Clone(f.lists[keys[0]]) {
+			deadline, ok := f.zsets[keys[1]][raw]
+			switch {
+			case !ok:
+				f.zadd(keys[1], raw, f.now+vis, false)
+			case deadline <= f.now:
+				var msg Message
+				_ = json.Unmarshal([]byte(raw), &msg)
+				f.lrem(keys[0], raw)
+				delete(f.zsets[keys[1]], raw)
+				pending := keys[3+int(msg.Priority)]
+				f.lists[pending] = append(f.lists[pending], raw)
+				n++
+			}
+		}
+		if len(f.lists[keys[0]]) == 0 {
+			if seen, ok := f.zsets[keys[2]][argv[1]]; !ok || seen+vis <= f.now {
+				delete(f.zsets[keys[2]], argv[1])
+			}
+		}
+		redistest.Int(cn, n)
+	default:
+		redistest.Error(cn, "NOSCRIPT No matching script")
+	}
+}
+
+func TestDequeuePriorities(t *testing.T) {
+	f := newFakeRedis()
+	q := New(redistest.NewClient(f.handle), "jobs", nil)
+	ctx := context.Background()
+
+	if _, err := q.Enqueue(ctx, []byte("n1"), Normal); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := q.EnqueueBatch(ctx, []Job{
+		{Body: []byte("l1"), Priority: Low},
+		{Body: []byte("h1"), Priority: High},
+		{Body: []byte("n2"), Priority: Normal},
+		{Body: []byte("h2"), Priority: High},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 4 || msgs[1].Priority != High {
+		t.Fatalf("got %v", msgs)
+	}
+	// one LPUSH per priority
+	if f.lpushs != 1+3 {
+		t.Fatalf("got %d LPUSH, want 4", f.lpushs)
+	}
+
+	var got []string
+	for range 5 {
+		msg, err := q.Dequeue(ctx, "w1", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, string(msg.Body))
+	}
+	if want := []string{"h1", "h2", "n1", "n2", "l1"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if _, err := q.Dequeue(ctx, "w1", 20*time.Millisecond); !errors.Is(err, ErrNoMessage) {
+		t.Fatalf("got %v, want ErrNoMessage", err)
+	}
+}
+
+func TestDequeueBlocking(t *testing.T) {
+	f := newFakeRedis()
+	q := New(redistest.NewClient(f.handle), "jobs", nil)
+	ctx := context.Background()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = q.Enqueue(ctx, []byte("h1"), High)
+	}()
+	msg, err := q.Dequeue(ctx, "w1", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(msg.Body) != "h1" {
+		t.Fatalf("got %q", msg.Body)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.zsets[q.deadlinesKey()][msg.raw]; !ok {
+		t.Fatal("no deadline set after BLMOVE")
+	}
+	if _, ok := f.zsets[q.workersKey()]["w1"]; !ok {
+		t.Fatal("worker not registered")
+	}
+}
+
+func TestAckRequeue(t *testing.T) {
+	f := newFakeRedis()
+	q := New(redistest.NewClient(f.handle), "jobs", &Options{VisibilityTimeout: time.Minute})
+	ctx := context.Background()
+
+	if _, err := q.Enqueue(ctx, []byte("a"), Normal); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := q.Dequeue(ctx, "w1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Ack(ctx, msg); err != nil {
+		t.Fatal(err)
+	}
+	f.mu.Lock()
+	if len(f.lists[q.processingKey("w1")]) != 0 || len(f.zsets[q.deadlinesKey()]) != 0 {
+		t.Fatal("acked message left")
+	}
+	f.mu.Unlock()
+
+	if _, err := q.Enqueue(ctx, []byte("b"), Low); err != nil {
+		t.Fatal(err)
+	}
+	msg, err = q.Dequeue(ctx, "w1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// not yet past the visibility timeout
+	if n, err := q.Requeue(ctx); err != nil || n != 0 {
+		t.Fatalf("got %d, %v", n, err)
+	}
+	f.mu.Lock()
+	f.now += time.Minute.Milliseconds()
+	f.mu.Unlock()
+	if n, err := q.Requeue(ctx); err != nil || n != 1 {
+		t.Fatalf("got %d, %v, want 1 requeued", n, err)
+	}
+	if err := q.Ack(ctx, msg); !errors.Is(err, ErrRequeued) {
+		t.Fatalf("got %v, want ErrRequeued", err)
+	}
+	f.mu.Lock()
+	if _, ok := f.zsets[q.workersKey()]["w1"]; ok {
+		t.Fatal("idle worker not forgotten")
+	}
+	f.mu.Unlock()
+
+	again, err := q.Dequeue(ctx, "w2", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != msg.ID || again.Priority != Low {
+		t.Fatalf("got %+v, want %+v", again, msg)
+	}
+}
+
+func TestOptionsPollInterval(t *testing.T) {
+	opt := &Options{VisibilityTimeout: time.Second, PollInterval: 5 * time.Second}
+	opt.init()
+	if opt.PollInterval != 500*time.Millisecond {
+		t.Fatalf("got %v, want half the visibility timeout", opt.PollInterval)
+	}
+}
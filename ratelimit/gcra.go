@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	go_redis "github.com/mingolm/go-redis"
+)
+
+// gcraScript stores the theoretical arrival time (TAT) of the next event,
+// in seconds since 2017 to keep the float precise, using the server clock.
+var gcraScript = go_redis.NewScript(`
+redis.replicate_commands()
+
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local period = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+
+local emission_interval = period / rate
+local increment = emission_interval * cost
+local burst_offset = emission_interval * burst
+
+local now = redis.call("TIME")
+now = (now[1] - 1483228800) + (now[2] / 1000000)
+
+local tat = redis.call("GET", KEYS[1])
+if not tat then
+	tat = now
+else
+	tat = math.max(tonumber(tat), now)
+end
+
+local new_tat = tat + increment
+local diff = now - (new_tat - burst_offset)
+if diff < 0 then
+	return {0, 0, math.ceil(-diff * 1000), math.ceil((tat - now) * 1000)}
+end
+
+local reset_after = new_tat - now
+redis.call("SET", KEYS[1], new_tat, "PX", math.ceil(reset_after * 1000))
+return {1, math.floor(diff / emission_interval), -1, math.ceil(reset_after * 1000)}
+`)
+
+// GCRA is the generic cell rate algorithm, equivalent to a token bucket
+// of Limit.Burst tokens refilled at Limit.Rate per Limit.Period, storing a
+// single timestamp per key.
+type GCRA struct {
+	rdb go_redis.Cmdable
+}
+
+var _ Limiter = (*GCRA)(nil)
+
+func NewGCRA(rdb go_redis.Cmdable) *GCRA {
+	return &GCRA{rdb: rdb}
+}
+
+func (l *GCRA) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	return l.AllowN(ctx, key, limit, 1)
+}
+
+// AllowN allows n events at once, or none.
+func (l *GCRA) AllowN(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	if err := limit.validate(); err != nil {
+		return nil, err
+	}
+	vals, err := gcraScript.Run(ctx, l.rdb, []string{key},
+		limit.burst(), limit.Rate, limit.Period.Seconds(), n).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	return newResult(limit, vals)
+}
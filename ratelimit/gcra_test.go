@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/mingolm/go-redis/internal/redistest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGCRA(t *testing.T) {
+	f := &fakeScript{script: gcraScript, replies: [][]int64{
+		{1, 17, -1, 300},
+		{0, 0, 150, 2000},
+		{1, 4, -1, 12000},
+		{1},
+	}}
+	l := NewGCRA(redistest.NewClient(f.handle))
+	ctx := context.Background()
+	limit := Limit{Rate: 10, Burst: 20, Period: time.Second}
+
+	res, err := l.AllowN(ctx, "k", limit, 3)
+	checkResult(t, res, err, Result{Limit: limit, Allowed: true, Remaining: 17, RetryAfter: -1, ResetAfter: 300 * time.Millisecond})
+	// burst, rate, period in seconds, cost
+	if want := []string{"20", "10", "1", "3"}; !reflect.DeepEqual(f.lastArgv(), want) {
+		t.Fatalf("got argv %v, want %v", f.lastArgv(), want)
+	}
+
+	res, err = l.AllowN(ctx, "k", limit, 3)
+	checkResult(t, res, err, Result{Limit: limit, Allowed: false, Remaining: 0, RetryAfter: 150 * time.Millisecond, ResetAfter: 2 * time.Second})
+
+	// the burst defaults to the rate
+	limit = PerMinute(5)
+	res, err = l.Allow(ctx, "k", limit)
+	checkResult(t, res, err, Result{Limit: limit, Allowed: true, Remaining: 4, RetryAfter: -1, ResetAfter: 12 * time.Second})
+	if want := []string{"5", "5", "60", "1"}; !reflect.DeepEqual(f.lastArgv(), want) {
+		t.Fatalf("got argv %v, want %v", f.lastArgv(), want)
+	}
+
+	if _, err := l.Allow(ctx, "k", limit); err == nil {
+		t.Fatal("want error for short reply")
+	}
+	if _, err := l.Allow(ctx, "k", limit); err == nil {
+		t.Fatal("want error for script error")
+	}
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// KeyFunc extracts the key requests are limited by.
+type KeyFunc func(r *http.Request) string
+
+// ClientIP keys requests by the address of the peer. Behind a proxy, use
+// a KeyFunc reading the header it sets instead.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Middleware limits requests per key, ClientIP if keyFunc is nil,
+// responding 429 Too Many Requests with a Retry-After header when denied.
+// Responses carry the RateLimit-Limit, RateLimit-Remaining,
+// RateLimit-Reset and RateLimit-Policy headers of the IETF draft.
+// Requests are let through when Redis fails.
+//
+//	limited := ratelimit.Middleware(ratelimit.NewGCRA(redis), ratelimit.PerSecond(10), nil)
+//	http.ListenAndServe(":8080", limited(mux))
+func Middleware(l Limiter, limit Limit, keyFunc KeyFunc) func(http.Handler) http.Handler {
+	if keyFunc == nil {
+		keyFunc = ClientIP
+	}
+	policy := strconv.Itoa(limit.Rate) + ";w=" + strconv.Itoa(seconds(limit.Period))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res, err := l.Allow(r.Context(), "ratelimit:http:"+keyFunc(r), limit)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(limit.Rate))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(seconds(res.ResetAfter)))
+			h.Set("RateLimit-Policy", policy)
+			if !res.Allowed {
+				h.Set("Retry-After", strconv.Itoa(seconds(res.RetryAfter)))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// seconds rounds up, the headers being in whole seconds.
+func seconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
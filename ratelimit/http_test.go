@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeLimiter struct {
+	keys []string
+	res  *Result
+	err  error
+}
+
+func (l *fakeLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	l.keys = append(l.keys, key)
+	return l.res, l.err
+}
+
+func TestMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	serve := func(l Limiter, keyFunc KeyFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.0.0.1:5123"
+		rec := httptest.NewRecorder()
+		Middleware(l, PerMinute(100), keyFunc)(ok).ServeHTTP(rec, req)
+		return rec
+	}
+
+	l := &fakeLimiter{res: &Result{Allowed: true, Remaining: 99, RetryAfter: -1, ResetAfter: 600 * time.Millisecond}}
+	rec := serve(l, nil)
+	if rec.Code != http.StatusNoContent || l.keys[0] != "ratelimit:http:10.0.0.1" {
+		t.Fatalf("got %d for key %q", rec.Code, l.keys[0])
+	}
+	for h, want := range map[string]string{
+		"RateLimit-Limit":     "100",
+		"RateLimit-Remaining": "99",
+		"RateLimit-Reset":     "1",
+		"RateLimit-Policy":    "100;w=60",
+		"Retry-After":         "",
+	} {
+		if got := rec.Header().Get(h); got != want {
+			t.Errorf("%s: got %q, want %q", h, got, want)
+		}
+	}
+
+	l = &fakeLimiter{res: &Result{Allowed: false, RetryAfter: 1500 * time.Millisecond, ResetAfter: time.Minute}}
+	rec = serve(l, func(r *http.Request) string { return "user:1" })
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "2" {
+		t.Fatalf("got %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if l.keys[0] != "ratelimit:http:user:1" {
+		t.Fatalf("got key %q", l.keys[0])
+	}
+
+	// fails open
+	rec = serve(&fakeLimiter{err: errors.New("down")}, nil)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("got %d with Redis down", rec.Code)
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	res, err := newResult(PerSecond(10), []int64{0, 0, 250, 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Allowed || res.RetryAfter != 250*time.Millisecond || res.ResetAfter != time.Second {
+		t.Fatalf("got %+v", res)
+	}
+	if res, _ = newResult(PerSecond(10), []int64{1, 9, -1, 100}); !res.Allowed || res.RetryAfter != -1 || res.Remaining != 9 {
+		t.Fatalf("got %+v", res)
+	}
+	if _, err := newResult(PerSecond(10), []int64{1}); err == nil {
+		t.Fatal("want error for short reply")
+	}
+	if _, err := NewGCRA(nil).Allow(context.Background(), "k", Limit{Rate: 0, Period: time.Second}); err == nil {
+		t.Fatal("want error for invalid limit")
+	}
+}
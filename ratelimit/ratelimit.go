@@ -0,0 +1,91 @@
+// Package ratelimit limits the rate of events per key across processes,
+// each decision taking a single round trip to Redis:
+//
+//	limiter := ratelimit.NewGCRA(redis)
+//	res, err := limiter.Allow(ctx, "api:"+userID, ratelimit.PerMinute(100))
+//	if err == nil && !res.Allowed {
+//		// retry after res.RetryAfter
+//	}
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Limit allows Rate events per Period, and bursts of up to Burst events.
+// Burst is only used by GCRA; it defaults to Rate.
+type Limit struct {
+	Rate   int
+	Burst  int
+	Period time.Duration
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d req/%s (burst %d)", l.Rate, l.Period, l.burst())
+}
+
+func (l Limit) validate() error {
+	if l.Rate <= 0 || l.Period <= 0 {
+		return fmt.Errorf("ratelimit: invalid limit %s", l)
+	}
+	return nil
+}
+
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Rate
+}
+
+func PerSecond(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Second}
+}
+
+func PerMinute(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Minute}
+}
+
+func PerHour(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Hour}
+}
+
+type Result struct {
+	Limit Limit
+	// Allowed reports whether the event is allowed.
+	Allowed bool
+	// Remaining is the number of events allowed right after this one.
+	Remaining int
+	// RetryAfter is the time until the next event is allowed, -1 if it
+	// is allowed now.
+	RetryAfter time.Duration
+	// ResetAfter is the time until the limiter is back to its initial
+	// state, where Remaining is the full burst.
+	ResetAfter time.Duration
+}
+
+// Limiter is implemented by GCRA, SlidingWindow and FixedWindow.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// newResult parses the {allowed, remaining, retry after ms, reset after ms}
+// reply of the limiter scripts.
+func newResult(limit Limit, vals []int64) (*Result, error) {
+	if len(vals) != 4 {
+		return nil, fmt.Errorf("ratelimit: got %d elements in reply, want 4", len(vals))
+	}
+	res := &Result{
+		Limit:      limit,
+		Allowed:    vals[0] != 0,
+		Remaining:  int(vals[1]),
+		RetryAfter: -1,
+		ResetAfter: time.Duration(vals[3]) * time.Millisecond,
+	}
+	if vals[2] >= 0 {
+		res.RetryAfter = time.Duration(vals[2]) * time.Millisecond
+	}
+	return res, nil
+}
@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	go_redis "github.com/mingolm/go-redis"
+	"github.com/mingolm/go-redis/internal/redistest"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// fakeScript replies to the runs of script with the given replies in
+// turn, recording their ARGV.
+type fakeScript struct {
+	script *go_redis.Script
+
+	mu      sync.Mutex
+	replies [][]int64
+	argv    [][]string
+}
+
+func (f *fakeScript) handle(cn net.Conn, args []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// EVALSHA sha 1 key args...
+	if args[0] != "EVALSHA" || args[1] != f.script.Hash() || len(f.replies) == 0 {
+		redistest.Error(cn, "ERR unexpected command")
+		return
+	}
+	f.argv = append(f.argv, args[4:])
+	redistest.Ints(cn, f.replies[0]...)
+	f.replies = f.replies[1:]
+}
+
+func (f *fakeScript) lastArgv() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.argv[len(f.argv)-1]
+}
+
+func checkResult(t *testing.T, res *Result, err error, want Result) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*res, want) {
+		t.Fatalf("got %+v, want %+v", *res, want)
+	}
+}
@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	go_redis "github.com/mingolm/go-redis"
+)
+
+// slidingWindowScript keeps the events of the last window in a sorted
+// set scored by their time in milliseconds.
+var slidingWindowScript = go_redis.NewScript(`
+redis.replicate_commands()
+
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+
+local t = redis.call("TIME")
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+if count < limit then
+	redis.call("ZADD", KEYS[1], now, ARGV[3])
+	redis.call("PEXPIRE", KEYS[1], window)
+	-- the event just logged is the last to leave the window
+	return {1, limit - count - 1, -1, window}
+end
+
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+local newest = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+return {0, 0, tonumber(oldest[2]) + window - now, tonumber(newest[2]) + window - now}
+`)
+
+// SlidingWindow allows Limit.Rate events in any span of Limit.Period,
+// logging the time of every allowed event.
+type SlidingWindow struct {
+	rdb go_redis.Cmdable
+}
+
+var _ Limiter = (*SlidingWindow)(nil)
+
+func NewSlidingWindow(rdb go_redis.Cmdable) *SlidingWindow {
+	return &SlidingWindow{rdb: rdb}
+}
+
+func (l *SlidingWindow) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	if err := limit.validate(); err != nil {
+		return nil, err
+	}
+	// unique member, events of the same millisecond are distinct
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+
+	vals, err := slidingWindowScript.Run(ctx, l.rdb, []string{key},
+		limit.Rate, limit.Period.Milliseconds(), hex.EncodeToString(b[:])).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	return newResult(limit, vals)
+}
+
+// fixedWindowScript counts the events of a window starting with its
+// first event.
+var fixedWindowScript = go_redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+
+local n = redis.call("INCR", KEYS[1])
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < 0 then
+	redis.call("PEXPIRE", KEYS[1], window)
+	ttl = window
+end
+
+if n > limit then
+	return {0, 0, ttl, ttl}
+end
+return {1, limit - n, -1, ttl}
+`)
+
+// FixedWindow allows Limit.Rate events per window of Limit.Period,
+// opened by the first event. It is the cheapest, but allows up to twice
+// the rate across the boundary of two windows.
+type FixedWindow struct {
+	rdb go_redis.Cmdable
+}
+
+var _ Limiter = (*FixedWindow)(nil)
+
+func NewFixedWindow(rdb go_redis.Cmdable) *FixedWindow {
+	return &FixedWindow{rdb: rdb}
+}
+
+func (l *FixedWindow) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	if err := limit.validate(); err != nil {
+		return nil, err
+	}
+	vals, err := fixedWindowScript.Run(ctx, l.rdb, []string{key},
+		limit.Rate, limit.Period.Milliseconds()).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	return newResult(limit, vals)
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/mingolm/go-redis/internal/redistest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSlidingWindow(t *testing.T) {
+	f := &fakeScript{script: slidingWindowScript, replies: [][]int64{
+		{1, 1, -1, 1000},
+		{0, 0, 500, 900},
+	}}
+	l := NewSlidingWindow(redistest.NewClient(f.handle))
+	ctx := context.Background()
+	limit := Limit{Rate: 2, Period: time.Second}
+
+	res, err := l.Allow(ctx, "k", limit)
+	checkResult(t, res, err, Result{Limit: limit, Allowed: true, Remaining: 1, RetryAfter: -1, ResetAfter: time.Second})
+	// limit, window in ms, unique member
+	argv := f.lastArgv()
+	if len(argv) != 3 || argv[0] != "2" || argv[1] != "1000" || len(argv[2]) != 16 {
+		t.Fatalf("got argv %v", argv)
+	}
+
+	res, err = l.Allow(ctx, "k", limit)
+	checkResult(t, res, err, Result{Limit: limit, Allowed: false, Remaining: 0, RetryAfter: 500 * time.Millisecond, ResetAfter: 900 * time.Millisecond})
+	if f.lastArgv()[2] == argv[2] {
+		t.Fatal("member reused")
+	}
+}
+
+func TestFixedWindow(t *testing.T) {
+	f := &fakeScript{script: fixedWindowScript, replies: [][]int64{
+		{1, 4, -1, 60000},
+		{0, 0, 700, 700},
+	}}
+	l := NewFixedWindow(redistest.NewClient(f.handle))
+	ctx := context.Background()
+	limit := PerMinute(5)
+
+	res, err := l.Allow(ctx, "k", limit)
+	checkResult(t, res, err, Result{Limit: limit, Allowed: true, Remaining: 4, RetryAfter: -1, ResetAfter: time.Minute})
+	// limit, window in ms
+	if want := []string{"5", "60000"}; !reflect.DeepEqual(f.lastArgv(), want) {
+		t.Fatalf("got argv %v, want %v", f.lastArgv(), want)
+	}
+
+	res, err = l.Allow(ctx, "k", limit)
+	checkResult(t, res, err, Result{Limit: limit, Allowed: false, Remaining: 0, RetryAfter: 700 * time.Millisecond, ResetAfter: 700 * time.Millisecond})
+
+	if _, err := l.Allow(ctx, "k", Limit{Rate: 5}); err == nil {
+		t.Fatal("want error for invalid limit")
+	}
+}
@@ -3,9 +3,14 @@ package go_redis
 import (
 	"bufio"
 	"context"
+	"errors"
 	"github.com/mingolm/go-redis/pool"
 	"github.com/mingolm/go-redis/proto"
 	"go.uber.org/zap"
+	"io"
+	"net"
+	"sync/atomic"
+	"syscall"
 )
 
 func NewClient(opt *Options) *Redis {
@@ -14,16 +19,17 @@ func NewClient(opt *Options) *Redis {
 
 	r := &Redis{
 		opt: opt,
-		connPool: pool.NewPool(&pool.Options{
-			Dialer:          opt.Dialer,
-			PoolSize:        opt.PoolSize,
-			MinIdleConns:    opt.MinIdleConns,
-			MaxIdleConns:    opt.MaxIdleConns,
-			ConnMaxIdleTime: opt.ConnMaxIdleTime,
-			ConnMaxLifetime: opt.ConnMaxLifeTime,
-			Logger:          opt.Logger,
-		}),
 	}
+	r.connPool = pool.NewPool(&pool.Options{
+		Dialer:          opt.Dialer,
+		PoolSize:        opt.PoolSize,
+		MinIdleConns:    opt.MinIdleConns,
+		MaxIdleConns:    opt.MaxIdleConns,
+		ConnMaxIdleTime: opt.ConnMaxIdleTime,
+		ConnMaxLifetime: opt.ConnMaxLifeTime,
+		OnConnect:       r.initConn,
+		Logger:          opt.Logger,
+	})
 	r.cmdable = r.process
 
 	return r
@@ -34,30 +40,191 @@ type Redis struct {
 	opt      *Options
 	connPool pool.Pooler
 	logger   *zap.SugaredLogger
+	hello    atomic.Pointer[HelloInfo]
 }
 
 func (r *Redis) process(ctx context.Context, cmd Cmder) error {
-	err := r.connPool.WithConn(ctx, func(ctx context.Context, cn *pool.Conn) error {
-		if err := cn.WithWrite(ctx, func(ctx context.Context, wd *bufio.Writer) error {
-			return proto.NewWriter(wd).Write(ctx, cmd.Args())
-		}); err != nil {
-			return err
-		}
-
-		if err := cn.WithRead(ctx, func(ctx context.Context, rd *bufio.Reader) error {
-			val, err := proto.NewReader(rd).Read()
-			if err != nil {
+	var err error
+	for attempt := 0; attempt <= r.opt.maxRetries(); attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, retryBackoff(attempt)); err != nil {
 				return err
 			}
-			return cmd.ReadReply(val)
-		}); err != nil {
+		}
+
+		var sent bool
+		err = r.connPool.WithConn(ctx, func(ctx context.Context, cn *pool.Conn) error {
+			err := r.processConn(ctx, cn, cmd)
+			sent = cn.Written() > 0
+			return err
+		})
+		if err == nil || !shouldRetry(err, sent) {
+			return err
+		}
+		if _, ok := cmd.(nonRetryable); ok {
 			return err
 		}
+	}
+	return err
+}
 
-		return nil
-	})
+func (r *Redis) processConn(ctx context.Context, cn *pool.Conn, cmd Cmder) error {
+	if err := cn.WithWrite(ctx, func(ctx context.Context, wd *bufio.Writer) error {
+		writer := proto.AcquireWriter(wd)
+		defer proto.Release(writer)
+		return writer.Write(ctx, cmd.Args())
+	}); err != nil {
+		// the server may have received part of the request
+		cn.MarkBroken()
+		return err
+	}
+
+	if err := cn.WithRead(ctx, func(ctx context.Context, rd *bufio.Reader) error {
+		reader := proto.AcquireReader(rd)
+		defer proto.Release(reader)
+		err := r.readReply(ctx, reader, cmd)
+		if isBadConn(err) {
+			cn.MarkBroken()
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// readReply reads the reply of cmd into it.
+func (r *Redis) readReply(ctx context.Context, reader *proto.RESP, cmd Cmder) error {
+	reader.OnAttributes = nil
+	if r.opt.OnAttributes != nil {
+		reader.OnAttributes = func(attrs map[interface{}]interface{}) {
+			r.opt.OnAttributes(ctx, cmd, attrs)
+		}
+	}
+	if rr, ok := cmd.(RESPReader); ok {
+		return rr.ReadRESP(reader)
+	}
+	val, err := reader.Read()
 	if err != nil {
 		return err
 	}
+	return cmd.ReadReply(val)
+}
+
+// isBadConn reports whether err left the connection in an unknown state,
+// as opposed to a reply of the server.
+func isBadConn(err error) bool {
+	return err != nil && err != proto.Nil && !proto.IsRedisError(err)
+}
+
+// initConn prepares a freshly dialed connection before it enters the pool:
+// negotiate the protocol, authenticate, select the database and identify
+// the client.
+func (r *Redis) initConn(ctx context.Context, cn *pool.Conn) error {
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		return r.processConn(ctx, cn, cmd)
+	})
+
+	hello := r.helloCmd(ctx)
+	if err := c(ctx, hello); err != nil {
+		// a server without HELLO (< 6.0) or without the requested protocol
+		// replies with an error: set the connection up the RESP2 way
+		if !proto.IsRedisError(err) {
+			return err
+		}
+		if err := r.initConnRESP2(ctx, c); err != nil {
+			return err
+		}
+	} else {
+		r.hello.Store(hello.result)
+	}
+
+	if r.opt.DB > 0 {
+		cmd := newStatusCmd(ctx, "SELECT", r.opt.DB)
+		if cmd.err = c(ctx, cmd); cmd.err != nil {
+			return cmd.err
+		}
+	}
+
+	if !r.opt.DisableIdentity {
+		// CLIENT SETINFO requires Redis 7.2, older servers reply with an
+		// error that must not prevent the connection from being used.
+		for _, attr := range [][2]string{{"lib-name", LibName}, {"lib-ver", Version}} {
+			if err := c.ClientSetInfo(ctx, attr[0], attr[1]).Err(); err != nil {
+				r.opt.Logger.Debugw("client setinfo failed",
+					"attr", attr[0],
+					"err", err,
+				)
+			}
+		}
+	}
+
 	return nil
 }
+
+func (r *Redis) helloCmd(ctx context.Context) *HelloCmd {
+	args := []interface{}{"HELLO", r.opt.Protocol}
+	if r.opt.Password != "" {
+		username := r.opt.Username
+		if username == "" {
+			username = "default"
+		}
+		args = append(args, "auth", username, r.opt.Password)
+	}
+	if r.opt.ClientName != "" {
+		args = append(args, "setname", r.opt.ClientName)
+	}
+	return &HelloCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (r *Redis) initConnRESP2(ctx context.Context, c cmdable) error {
+	if r.opt.Password != "" {
+		args := []interface{}{"AUTH", r.opt.Password}
+		if r.opt.Username != "" {
+			args = []interface{}{"AUTH", r.opt.Username, r.opt.Password}
+		}
+		cmd := newStatusCmd(ctx, args...)
+		if cmd.err = c(ctx, cmd); cmd.err != nil {
+			return cmd.err
+		}
+	}
+
+	if r.opt.ClientName != "" {
+		if err := c.ClientSetName(ctx, r.opt.ClientName).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Hello returns the HELLO reply of the most recently established
+// connection, or nil if the server does not support HELLO.
+func (r *Redis) Hello() *HelloInfo {
+	return r.hello.Load()
+}
+
+// shouldRetry reports whether a failed command may succeed on another
+// attempt without running twice: the server refused it as temporarily
+// unable to serve it, or the connection was found closed before any byte
+// of the request was sent. Once sent, the server may have run the command
+// before the connection dropped, so it is not retried.
+func shouldRetry(err error, sent bool) bool {
+	switch {
+	case errors.Is(err, proto.ErrLoading), errors.Is(err, proto.ErrTryAgain):
+		return true
+	case sent:
+		return false
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.ErrClosedPipe),
+		errors.Is(err, net.ErrClosed), errors.Is(err, syscall.EPIPE), errors.Is(err, syscall.ECONNRESET):
+		return true
+	default:
+		return false
+	}
+}
@@ -3,8 +3,12 @@ package go_redis
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/mingolm/go-redis/proto"
+	"io"
+	"net"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 )
@@ -40,3 +44,62 @@ func BenchmarkGet(b *testing.B) {
 		}
 	}
 }
+
+func TestRetry(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		conns []net.Conn
+		calls = map[string]int{}
+		ready = make(chan struct{}, 10)
+	)
+	dialer := fakeServer(func(cn net.Conn, args []string) {
+		mu.Lock()
+		calls[args[0]]++
+		mu.Unlock()
+		switch args[0] {
+		case "HELLO":
+			mu.Lock()
+			conns = append(conns, cn)
+			mu.Unlock()
+			fmt.Fprint(cn, "-ERR unknown command 'HELLO'\r\n")
+		case "CLIENT":
+			fmt.Fprint(cn, "+OK\r\n")
+			if args[len(args)-2] == "lib-ver" {
+				ready <- struct{}{}
+			}
+		case "SHUTDOWN", "LPUSH":
+			// run, then the connection drops before the reply
+			cn.Close()
+		default:
+			fmt.Fprint(cn, "+OK\r\n")
+		}
+	})
+	r := NewClient(&Options{Dialer: dialer, PoolSize: 1, MinIdleConns: 1, MaxIdleConns: 1})
+	ctx := context.Background()
+	<-ready
+
+	if err := r.Shutdown(ctx, "").Err(); err != nil {
+		t.Fatal(err)
+	}
+	<-ready
+	if err := r.LPush(ctx, "q", "x").Err(); !errors.Is(err, io.EOF) {
+		t.Fatalf("got %v, want EOF", err)
+	}
+	<-ready
+	mu.Lock()
+	if calls["SHUTDOWN"] != 1 || calls["LPUSH"] != 1 {
+		t.Fatalf("got %v, sent commands must not be retried", calls)
+	}
+	// the pooled connection is closed before the request is sent
+	conns[len(conns)-1].Close()
+	mu.Unlock()
+
+	if err := r.Set(ctx, "k", "v", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls["SET"] != 1 {
+		t.Fatalf("got %d SET, want 1", calls["SET"])
+	}
+}
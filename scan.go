@@ -0,0 +1,170 @@
+package go_redis
+
+import (
+	"encoding"
+	"fmt"
+	"github.com/mingolm/go-redis/internal/structtag"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// scanValue parses s into dst, a pointer to a string, []byte, integer,
+// float, bool, time.Time or time.Duration, or an encoding.BinaryUnmarshaler
+// / encoding.TextUnmarshaler. Values are parsed in the format the
+// argument writer of package proto produces (bool as 0/1, time.Time as
+// RFC3339Nano, time.Duration as nanoseconds).
+func scanValue(s string, dst interface{}) (err error) {
+	switch d := dst.(type) {
+	case nil:
+		return fmt.Errorf("redis: Scan(nil)")
+	case *string:
+		*d = s
+	case *[]byte:
+		*d = []byte(s)
+	case *int:
+		*d, err = strconv.Atoi(s)
+	case *int8:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 8)
+		*d = int8(n)
+	case *int16:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 16)
+		*d = int16(n)
+	case *int32:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 32)
+		*d = int32(n)
+	case *int64:
+		*d, err = strconv.ParseInt(s, 10, 64)
+	case *uint:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 0)
+		*d = uint(n)
+	case *uint8:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 8)
+		*d = uint8(n)
+	case *uint16:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 16)
+		*d = uint16(n)
+	case *uint32:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 32)
+		*d = uint32(n)
+	case *uint64:
+		*d, err = strconv.ParseUint(s, 10, 64)
+	case *float32:
+		var f float64
+		f, err = strconv.ParseFloat(s, 32)
+		*d = float32(f)
+	case *float64:
+		*d, err = strconv.ParseFloat(s, 64)
+	case *bool:
+		*d, err = strconv.ParseBool(s)
+	case *time.Time:
+		*d, err = time.Parse(time.RFC3339Nano, s)
+	case *time.Duration:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 64)
+		*d = time.Duration(n)
+	case encoding.BinaryUnmarshaler:
+		err = d.UnmarshalBinary([]byte(s))
+	case encoding.TextUnmarshaler:
+		err = d.UnmarshalText([]byte(s))
+	default:
+		return scanReflect(s, dst)
+	}
+	return err
+}
+
+// scanReflect handles pointers to named basic types, e.g. *Status with
+// type Status int.
+func scanReflect(s string, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("redis: Scan(non-pointer %T)", dst)
+	}
+	v := rv.Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("redis: can't unmarshal %T (implement encoding.BinaryUnmarshaler)", dst)
+	}
+	return nil
+}
+
+// structScanner sets the fields of a struct by their `redis:"name"` tag.
+type structScanner struct {
+	v      reflect.Value
+	fields map[string]int
+}
+
+func newStructScanner(dst interface{}) (*structScanner, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("redis: Scan(non-struct pointer %T)", dst)
+	}
+	v := rv.Elem()
+	return &structScanner{
+		v:      v,
+		fields: structTags(v.Type()),
+	}, nil
+}
+
+// scan sets the field tagged name; unknown names are ignored.
+func (s *structScanner) scan(name, value string) error {
+	i, ok := s.fields[name]
+	if !ok {
+		return nil
+	}
+	if err := scanValue(value, s.v.Field(i).Addr().Interface()); err != nil {
+		return fmt.Errorf("redis: scan field %q: %w", name, err)
+	}
+	return nil
+}
+
+var structTagsCache sync.Map // reflect.Type -> map[string]int
+
+// structTags maps the `redis:"name"` tags of t to field indexes.
+func structTags(t reflect.Type) map[string]int {
+	if fields, ok := structTagsCache.Load(t); ok {
+		return fields.(map[string]int)
+	}
+
+	fields := make(map[string]int)
+	for _, f := range structtag.Fields(t) {
+		fields[f.Name] = f.Index
+	}
+
+	structTagsCache.Store(t, fields)
+	return fields
+}
@@ -0,0 +1,260 @@
+package go_redis
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strconv"
+)
+
+type ScanCmdable interface {
+	Scan(ctx context.Context, cursor uint64, opt *ScanOptions) *ScanCmd
+	SScan(ctx context.Context, key string, cursor uint64, opt *ScanOptions) *ScanCmd
+	HScan(ctx context.Context, key string, cursor uint64, opt *ScanOptions) *ScanCmd
+	ZScan(ctx context.Context, key string, cursor uint64, opt *ScanOptions) *ScanCmd
+
+	ScanAll(ctx context.Context, match string) iter.Seq2[string, error]
+	SScanAll(ctx context.Context, key, match string) iter.Seq2[string, error]
+	HScanAll(ctx context.Context, key, match string) iter.Seq2[FieldValue, error]
+	ZScanAll(ctx context.Context, key, match string) iter.Seq2[Z, error]
+}
+
+type ScanOptions struct {
+	Match string
+	Count int64
+	// Type filters keys by type; only used by SCAN.
+	Type string
+}
+
+func (c cmdable) Scan(ctx context.Context, cursor uint64, opt *ScanOptions) *ScanCmd {
+	return c.scan(ctx, []interface{}{"SCAN", cursor}, opt)
+}
+
+func (c cmdable) SScan(ctx context.Context, key string, cursor uint64, opt *ScanOptions) *ScanCmd {
+	return c.scan(ctx, []interface{}{"SSCAN", key, cursor}, opt)
+}
+
+// HScan yields field, value, field, value, ...
+func (c cmdable) HScan(ctx context.Context, key string, cursor uint64, opt *ScanOptions) *ScanCmd {
+	return c.scan(ctx, []interface{}{"HSCAN", key, cursor}, opt)
+}
+
+// ZScan yields member, score, member, score, ...
+func (c cmdable) ZScan(ctx context.Context, key string, cursor uint64, opt *ScanOptions) *ScanCmd {
+	return c.scan(ctx, []interface{}{"ZSCAN", key, cursor}, opt)
+}
+
+func (c cmdable) scan(ctx context.Context, args []interface{}, opt *ScanOptions) *ScanCmd {
+	if opt != nil {
+		if opt.Match != "" {
+			args = append(args, "match", opt.Match)
+		}
+		if opt.Count > 0 {
+			args = append(args, "count", opt.Count)
+		}
+		if opt.Type != "" && args[0] == "SCAN" {
+			args = append(args, "type", opt.Type)
+		}
+	}
+	cmd := &ScanCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+		process: c,
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// ScanAll iterates the keys matching match. The first page is fetched
+// when the loop starts, each loop scanning anew.
+func (c cmdable) ScanAll(ctx context.Context, match string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		c.Scan(ctx, 0, &ScanOptions{Match: match}).Iterator().All(ctx)(yield)
+	}
+}
+
+func (c cmdable) SScanAll(ctx context.Context, key, match string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		c.SScan(ctx, key, 0, &ScanOptions{Match: match}).Iterator().All(ctx)(yield)
+	}
+}
+
+// FieldValue is a field of a hash and its value.
+type FieldValue struct {
+	Field string
+	Value string
+}
+
+func (c cmdable) HScanAll(ctx context.Context, key, match string) iter.Seq2[FieldValue, error] {
+	return func(yield func(FieldValue, error) bool) {
+		it := c.HScan(ctx, key, 0, &ScanOptions{Match: match}).Iterator()
+		for it.Next(ctx) {
+			field := it.Val()
+			if !it.Next(ctx) {
+				break
+			}
+			if !yield(FieldValue{Field: field, Value: it.Val()}, nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(FieldValue{}, err)
+		}
+	}
+}
+
+// ZScanAll yields the members with their score, the Member of each Z
+// being a string.
+func (c cmdable) ZScanAll(ctx context.Context, key, match string) iter.Seq2[Z, error] {
+	return func(yield func(Z, error) bool) {
+		it := c.ZScan(ctx, key, 0, &ScanOptions{Match: match}).Iterator()
+		for it.Next(ctx) {
+			member := it.Val()
+			if !it.Next(ctx) {
+				break
+			}
+			score, err := strconv.ParseFloat(it.Val(), 64)
+			if err != nil {
+				yield(Z{}, err)
+				return
+			}
+			if !yield(Z{Score: score, Member: member}, nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(Z{}, err)
+		}
+	}
+}
+
+type ScanCmd struct {
+	*baseCmd
+	process cmdable
+	page    []string
+	cursor  uint64
+}
+
+func (cmd *ScanCmd) ReadReply(val interface{}) error {
+	vals, err := toSlice(val)
+	if err != nil {
+		return err
+	}
+	if len(vals) != 2 {
+		return fmt.Errorf("redis: got %d elements in scan reply, want 2", len(vals))
+	}
+	cursor, err := toString(vals[0])
+	if err != nil {
+		return err
+	}
+	if cmd.cursor, err = strconv.ParseUint(cursor, 10, 64); err != nil {
+		return err
+	}
+	items, err := toSlice(vals[1])
+	if err != nil {
+		return err
+	}
+	cmd.page = make([]string, len(items))
+	for i, item := range items {
+		if cmd.page[i], err = toString(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cmd *ScanCmd) String() string {
+	return fmt.Sprint(cmd.cursor, cmd.page)
+}
+
+func (cmd *ScanCmd) Result() (keys []string, cursor uint64, err error) {
+	return cmd.page, cmd.cursor, cmd.err
+}
+
+// Iterator follows the cursor of cmd until the scan is complete.
+func (cmd *ScanCmd) Iterator() *ScanIterator {
+	return &ScanIterator{
+		cmd: cmd,
+	}
+}
+
+// ScanIterator walks every page of a SCAN-family command:
+//
+//	it := redis.Scan(ctx, 0, &ScanOptions{Match: "user:*"}).Iterator()
+//	for it.Next(ctx) {
+//		key := it.Val()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type ScanIterator struct {
+	cmd *ScanCmd
+	pos int
+}
+
+func (it *ScanIterator) Err() error {
+	return it.cmd.Err()
+}
+
+// Next advances to the next element, fetching further pages as needed.
+func (it *ScanIterator) Next(ctx context.Context) bool {
+	for {
+		if it.cmd.Err() != nil {
+			return false
+		}
+		if it.pos < len(it.cmd.page) {
+			it.pos++
+			return true
+		}
+		if it.cmd.cursor == 0 {
+			return false
+		}
+
+		args := make([]interface{}, len(it.cmd.args))
+		copy(args, it.cmd.args)
+		// the cursor follows the command name, and the key for SSCAN/HSCAN/ZSCAN
+		if args[0] == "SCAN" {
+			args[1] = it.cmd.cursor
+		} else {
+			args[2] = it.cmd.cursor
+		}
+		next := &ScanCmd{
+			baseCmd: &baseCmd{
+				ctx:  ctx,
+				args: args,
+			},
+			process: it.cmd.process,
+		}
+		next.err = next.process(ctx, next)
+		it.cmd = next
+		it.pos = 0
+	}
+}
+
+// Val returns the element at the current position.
+func (it *ScanIterator) Val() string {
+	if it.pos == 0 || it.pos > len(it.cmd.page) {
+		return ""
+	}
+	return it.cmd.page[it.pos-1]
+}
+
+// All adapts the iterator for range-over-func. Iteration ends after the
+// first error; breaking out of the loop stops fetching pages. Like the
+// iterator, it can be ranged once.
+//
+//	for key, err := range redis.ScanAll(ctx, "user:*") {
+//	}
+func (it *ScanIterator) All(ctx context.Context) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for it.Next(ctx) {
+			if !yield(it.Val(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield("", err)
+		}
+	}
+}
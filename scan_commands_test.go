@@ -0,0 +1,102 @@
+package go_redis
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestScanAll(t *testing.T) {
+	pages := map[uint64][]interface{}{
+		0: {"3", []interface{}{"a", "b"}},
+		3: {"7", []interface{}{}},
+		7: {"0", []interface{}{"c"}},
+	}
+	var calls int
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		calls++
+		cursor := cmd.Args()[1].(uint64)
+		return cmd.ReadReply(pages[cursor])
+	})
+
+	var keys []string
+	for key, err := range c.ScanAll(context.Background(), "*") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("got %v, want %v", keys, want)
+	}
+	if calls != 3 {
+		t.Fatalf("got %d calls, want 3", calls)
+	}
+
+	calls = 0
+	for range c.ScanAll(context.Background(), "*") {
+		break
+	}
+	if calls != 1 {
+		t.Fatalf("got %d calls after break, want 1", calls)
+	}
+}
+
+func TestScanAllLazy(t *testing.T) {
+	var calls int
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		calls++
+		return cmd.ReadReply([]interface{}{"0", []interface{}{"a"}})
+	})
+
+	seq := c.ScanAll(context.Background(), "*")
+	if calls != 0 {
+		t.Fatalf("got %d calls before ranging, want 0", calls)
+	}
+	for i := 0; i < 2; i++ {
+		var keys []string
+		for key, err := range seq {
+			if err != nil {
+				t.Fatal(err)
+			}
+			keys = append(keys, key)
+		}
+		if want := []string{"a"}; !reflect.DeepEqual(keys, want) {
+			t.Fatalf("range %d: got %v, want %v", i, keys, want)
+		}
+	}
+}
+
+func TestScanAllPairs(t *testing.T) {
+	pages := map[uint64][]interface{}{
+		0: {"5", []interface{}{"f1", "1", "f2", "2.5"}},
+		5: {"0", []interface{}{"f3", "3"}},
+	}
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		cursor := cmd.Args()[2].(uint64)
+		return cmd.ReadReply(pages[cursor])
+	})
+	ctx := context.Background()
+
+	var fields []FieldValue
+	for fv, err := range c.HScanAll(ctx, "h", "*") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		fields = append(fields, fv)
+	}
+	if want := []FieldValue{{"f1", "1"}, {"f2", "2.5"}, {"f3", "3"}}; !reflect.DeepEqual(fields, want) {
+		t.Fatalf("got %v, want %v", fields, want)
+	}
+
+	var members []Z
+	for z, err := range c.ZScanAll(ctx, "z", "*") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		members = append(members, z)
+	}
+	if want := []Z{{1, "f1"}, {2.5, "f2"}, {3, "f3"}}; !reflect.DeepEqual(members, want) {
+		t.Fatalf("got %v, want %v", members, want)
+	}
+}
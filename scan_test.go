@@ -0,0 +1,210 @@
+package go_redis
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/mingolm/go-redis/proto"
+	"net"
+	"testing"
+	"time"
+)
+
+type scanUser struct {
+	Name    string    `redis:"name"`
+	Age     int       `redis:"age"`
+	Score   float64   `redis:"score"`
+	Admin   bool      `redis:"admin"`
+	Created time.Time `redis:"created"`
+	IP      net.IP    `redis:"ip"`
+	Ignored string
+}
+
+func TestMapStringStringCmdScan(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 123, time.UTC)
+	cmd := newMapStringStringCmd(context.Background(), "HGETALL", "user:1")
+	err := cmd.ReadReply(map[interface{}]interface{}{
+		"name":    "mingo",
+		"age":     "18",
+		"score":   "9.5",
+		"admin":   "1",
+		"created": created.Format(time.RFC3339Nano),
+		"ip":      "10.0.0.1",
+		"unknown": "x",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var u scanUser
+	if err := cmd.Scan(&u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "mingo" || u.Age != 18 || u.Score != 9.5 || !u.Admin || !u.Created.Equal(created) {
+		t.Fatalf("got %+v", u)
+	}
+	if !u.IP.Equal(net.ParseIP("10.0.0.1")) {
+		t.Fatalf("got ip %v", u.IP)
+	}
+
+	cmd.result["age"] = "old"
+	if err := cmd.Scan(&u); err == nil {
+		t.Fatal("want error for invalid int")
+	}
+	if err := cmd.Scan(u); err == nil {
+		t.Fatal("want error for non-pointer")
+	}
+}
+
+func TestSliceCmdScan(t *testing.T) {
+	cmd := newSliceCmd(context.Background(), "MGET", "name", "age", "admin")
+	if err := cmd.ReadReply([]interface{}{"mingo", nil, "0"}); err != nil {
+		t.Fatal(err)
+	}
+	u := scanUser{Age: 7, Admin: true}
+	if err := cmd.Scan(&u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "mingo" || u.Age != 7 || u.Admin {
+		t.Fatalf("got %+v", u)
+	}
+
+	cmd = newSliceCmd(context.Background(), "HMGET", "user:1", "score")
+	if err := cmd.ReadReply([]interface{}{"1.25"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Scan(&u); err != nil || u.Score != 1.25 {
+		t.Fatalf("got %+v, %v", u, err)
+	}
+}
+
+type scanLevel int
+
+func TestStringCmdScan(t *testing.T) {
+	scan := func(s string, dst interface{}) error {
+		cmd := newStringCmd(context.Background(), "GET", "k")
+		if err := cmd.ReadReply(s); err != nil {
+			t.Fatal(err)
+		}
+		return cmd.Scan(dst)
+	}
+
+	var (
+		i   int
+		u8  uint8
+		f   float64
+		b   bool
+		bs  []byte
+		d   time.Duration
+		tm  time.Time
+		lvl scanLevel
+	)
+	for _, tt := range []struct {
+		s   string
+		dst interface{}
+	}{
+		{"-42", &i}, {"255", &u8}, {"3.5", &f}, {"1", &b}, {"raw", &bs},
+		{"1500000000", &d}, {"2024-05-01T12:00:00Z", &tm}, {"3", &lvl},
+	} {
+		if err := scan(tt.s, tt.dst); err != nil {
+			t.Fatalf("scan %q into %T: %v", tt.s, tt.dst, err)
+		}
+	}
+	if i != -42 || u8 != 255 || f != 3.5 || !b || string(bs) != "raw" ||
+		d != 1500*time.Millisecond || tm.Year() != 2024 || lvl != 3 {
+		t.Fatalf("got %v %v %v %v %q %v %v %v", i, u8, f, b, bs, d, tm, lvl)
+	}
+
+	if err := scan("256", &u8); err == nil {
+		t.Fatal("want overflow error")
+	}
+	if err := scan("x", &struct{}{}); err == nil {
+		t.Fatal("want error for unsupported type")
+	}
+}
+
+func TestStringCmdConversions(t *testing.T) {
+	get := func(s string) *StringCmd {
+		cmd := newStringCmd(context.Background(), "GET", "k")
+		_ = cmd.ReadReply(s)
+		return cmd
+	}
+
+	if n, err := get("-7").Int(); err != nil || n != -7 {
+		t.Fatalf("Int: %v, %v", n, err)
+	}
+	if n, err := get("18446744073709551615").Uint64(); err != nil || n != 1<<64-1 {
+		t.Fatalf("Uint64: %v, %v", n, err)
+	}
+	if f, err := get("0.25").Float32(); err != nil || f != 0.25 {
+		t.Fatalf("Float32: %v, %v", f, err)
+	}
+	if b, err := get("0").Bool(); err != nil || b {
+		t.Fatalf("Bool: %v, %v", b, err)
+	}
+	if _, err := get("abc").Int64(); err == nil {
+		t.Fatal("Int64: want error")
+	}
+
+	// values round-trip through the argument writer
+	now := time.Now()
+	var buf bytes.Buffer
+	bw := bufio.NewWriter(&buf)
+	if err := proto.NewWriter(bw).Write(context.Background(), []interface{}{"SET", "k", now, true, 3.5}); err != nil {
+		t.Fatal(err)
+	}
+	_ = bw.Flush()
+	args, err := proto.NewReader(bufio.NewReader(&buf)).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vals := args.([]interface{})
+	if tm, err := get(vals[2].(string)).Time(); err != nil || !tm.Equal(now) {
+		t.Fatalf("Time: %v, %v", tm, err)
+	}
+	if b, err := get(vals[3].(string)).Bool(); err != nil || !b {
+		t.Fatalf("Bool: %v, %v", b, err)
+	}
+	if f, err := get(vals[4].(string)).Float64(); err != nil || f != 3.5 {
+		t.Fatalf("Float64: %v, %v", f, err)
+	}
+
+	cmd := get("v")
+	cmd.err = proto.Nil
+	if _, err := cmd.Bytes(); err != proto.Nil {
+		t.Fatalf("Bytes: got %v, want Nil", err)
+	}
+}
+
+// A struct written with HSET scans back from HGETALL, the field names
+// being parsed alike.
+func TestScanRoundTrip(t *testing.T) {
+	type user struct {
+		Name   string `redis:",omitempty"`
+		Age    int    `redis:"age,omitempty"`
+		Secret string `redis:"-"`
+	}
+	in := user{Name: "mingo", Age: 18, Secret: "s"}
+
+	var reply []interface{}
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		for _, arg := range cmd.Args()[2:] {
+			reply = append(reply, fmt.Sprint(arg))
+		}
+		return cmd.ReadReply(int64(len(reply) / 2))
+	})
+	c.HSet(context.Background(), "user:1", in)
+
+	cmd := newMapStringStringCmd(context.Background(), "HGETALL", "user:1")
+	if err := cmd.ReadReply(reply); err != nil {
+		t.Fatal(err)
+	}
+	var out user
+	if err := cmd.Scan(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out != (user{Name: "mingo", Age: 18}) {
+		t.Fatalf("got %+v from %v", out, reply)
+	}
+}
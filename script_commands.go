@@ -0,0 +1,166 @@
+package go_redis
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/mingolm/go-redis/proto"
+	"strings"
+)
+
+type ScriptingCmdable interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) *Cmd
+	EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *Cmd
+	EvalRO(ctx context.Context, script string, keys []string, args ...interface{}) *Cmd
+	EvalShaRO(ctx context.Context, sha1 string, keys []string, args ...interface{}) *Cmd
+	ScriptLoad(ctx context.Context, script string) *StringCmd
+	ScriptExists(ctx context.Context, hashes ...string) *BoolSliceCmd
+	ScriptFlush(ctx context.Context, mode string) *StatusCmd
+}
+
+func (c cmdable) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *Cmd {
+	cmd := newCmd(ctx, fcallArgs("EVAL", script, keys, args)...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *Cmd {
+	cmd := newCmd(ctx, fcallArgs("EVALSHA", sha1, keys, args)...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) EvalRO(ctx context.Context, script string, keys []string, args ...interface{}) *Cmd {
+	cmd := newCmd(ctx, fcallArgs("EVAL_RO", script, keys, args)...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) EvalShaRO(ctx context.Context, sha1 string, keys []string, args ...interface{}) *Cmd {
+	cmd := newCmd(ctx, fcallArgs("EVALSHA_RO", sha1, keys, args)...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// ScriptLoad returns the SHA1 digest of the cached script.
+func (c cmdable) ScriptLoad(ctx context.Context, script string) *StringCmd {
+	cmd := newStringCmd(ctx, "SCRIPT", "LOAD", script)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ScriptExists(ctx context.Context, hashes ...string) *BoolSliceCmd {
+	args := make([]interface{}, 2, 2+len(hashes))
+	args[0] = "SCRIPT"
+	args[1] = "EXISTS"
+	for _, hash := range hashes {
+		args = append(args, hash)
+	}
+	cmd := newBoolSliceCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ScriptFlush(ctx context.Context, mode string) *StatusCmd {
+	args := []interface{}{"SCRIPT", "FLUSH"}
+	if mode != "" {
+		args = append(args, strings.ToLower(mode))
+	}
+	cmd := newStatusCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+type BoolSliceCmd struct {
+	*baseCmd
+	result []bool
+}
+
+func newBoolSliceCmd(ctx context.Context, args ...interface{}) *BoolSliceCmd {
+	return &BoolSliceCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *BoolSliceCmd) ReadReply(val interface{}) error {
+	vals, err := toSlice(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = make([]bool, len(vals))
+	for i, v := range vals {
+		n, err := toInt64(v)
+		if err != nil {
+			return err
+		}
+		cmd.result[i] = n != 0
+	}
+	return nil
+}
+
+func (cmd *BoolSliceCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *BoolSliceCmd) Result() ([]bool, error) {
+	return cmd.result, cmd.err
+}
+
+// Script runs a Lua script by its SHA1 digest, sending the source only
+// when the server doesn't have it cached:
+//
+//	var incrBy = NewScript(`return redis.call("INCRBY", KEYS[1], ARGV[1])`)
+//	n, err := incrBy.Run(ctx, redis, []string{"counter"}, 2).Int64()
+type Script struct {
+	src  string
+	hash string
+}
+
+func NewScript(src string) *Script {
+	h := sha1.Sum([]byte(src))
+	return &Script{
+		src:  src,
+		hash: hex.EncodeToString(h[:]),
+	}
+}
+
+func (s *Script) Hash() string {
+	return s.hash
+}
+
+// Load caches the script on the server.
+func (s *Script) Load(ctx context.Context, c ScriptingCmdable) error {
+	return c.ScriptLoad(ctx, s.src).Err()
+}
+
+// Run calls EVALSHA, and EVAL if the script is not cached (e.g. after
+// SCRIPT FLUSH or a restart), which caches it for the next calls.
+func (s *Script) Run(ctx context.Context, c ScriptingCmdable, keys []string, args ...interface{}) *Cmd {
+	cmd := c.EvalSha(ctx, s.hash, keys, args...)
+	if !isNoScript(cmd.Err()) {
+		return cmd
+	}
+	return c.Eval(ctx, s.src, keys, args...)
+}
+
+// RunRO is Run with the read-only variants, which may run on replicas.
+func (s *Script) RunRO(ctx context.Context, c ScriptingCmdable, keys []string, args ...interface{}) *Cmd {
+	cmd := c.EvalShaRO(ctx, s.hash, keys, args...)
+	if !isNoScript(cmd.Err()) {
+		return cmd
+	}
+	return c.EvalRO(ctx, s.src, keys, args...)
+}
+
+func isNoScript(err error) bool {
+	var rerr proto.RedisError
+	if !errors.As(err, &rerr) {
+		return false
+	}
+	return strings.HasPrefix(string(rerr), "NOSCRIPT")
+}
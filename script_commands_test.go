@@ -0,0 +1,124 @@
+package go_redis
+
+import (
+	"context"
+	"github.com/mingolm/go-redis/proto"
+	"reflect"
+	"testing"
+)
+
+func TestScriptRun(t *testing.T) {
+	s := NewScript("return 1")
+	if s.Hash() != "e0e1f9fabfc9d4800c877a703b823ac0578ff8db" {
+		t.Fatalf("got hash %s", s.Hash())
+	}
+
+	var names []string
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		name := cmd.Args()[0].(string)
+		names = append(names, name)
+		if name == "EVALSHA" {
+			return proto.RedisError("NOSCRIPT No matching script. Please use EVAL.")
+		}
+		return cmd.ReadReply(int64(1))
+	})
+	n, err := s.Run(context.Background(), c, []string{"k"}).Int64()
+	if err != nil || n != 1 {
+		t.Fatalf("got %d, %v", n, err)
+	}
+	if len(names) != 2 || names[1] != "EVAL" {
+		t.Fatalf("got %v, want EVALSHA then EVAL", names)
+	}
+}
+
+func TestSetNX(t *testing.T) {
+	for _, tt := range []struct {
+		reply interface{}
+		want  bool
+	}{{"OK", true}, {proto.Nil, false}} {
+		c := cmdable(func(ctx context.Context, cmd Cmder) error {
+			if err, ok := tt.reply.(error); ok {
+				return err
+			}
+			return cmd.ReadReply(tt.reply)
+		})
+		ok, err := c.SetNX(context.Background(), "k", "v", 0).Result()
+		if err != nil || ok != tt.want {
+			t.Fatalf("reply %v: got %v, %v", tt.reply, ok, err)
+		}
+	}
+}
+
+func TestScriptRunRO(t *testing.T) {
+	s := NewScript("return redis.call('GET', KEYS[1])")
+	var sent [][]interface{}
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		sent = append(sent, cmd.Args())
+		if cmd.Args()[0] == "EVALSHA_RO" {
+			return proto.RedisError("NOSCRIPT No matching script. Please use EVAL.")
+		}
+		return cmd.ReadReply("v")
+	})
+	if v, err := s.RunRO(context.Background(), c, []string{"k"}, "a").Text(); err != nil || v != "v" {
+		t.Fatalf("got %q, %v", v, err)
+	}
+	want := [][]interface{}{
+		{"EVALSHA_RO", s.Hash(), 1, "k", "a"},
+		{"EVAL_RO", "return redis.call('GET', KEYS[1])", 1, "k", "a"},
+	}
+	if !reflect.DeepEqual(sent, want) {
+		t.Fatalf("got %v, want %v", sent, want)
+	}
+
+	// other errors are not retried with the source
+	sent = nil
+	c = cmdable(func(ctx context.Context, cmd Cmder) error {
+		sent = append(sent, cmd.Args())
+		return proto.RedisError("ERR boom")
+	})
+	if err := s.Run(context.Background(), c, nil).Err(); err == nil || len(sent) != 1 {
+		t.Fatalf("got %v after %d commands", err, len(sent))
+	}
+}
+
+func TestScriptCommands(t *testing.T) {
+	var args []interface{}
+	c := cmdable(func(ctx context.Context, cmd Cmder) error {
+		args = cmd.Args()
+		switch cmd := cmd.(type) {
+		case *BoolSliceCmd:
+			return cmd.ReadReply([]interface{}{int64(1), int64(0)})
+		case *StringCmd:
+			return cmd.ReadReply("e0e1f9fabfc9d4800c877a703b823ac0578ff8db")
+		}
+		return cmd.ReadReply("OK")
+	})
+	ctx := context.Background()
+
+	c.Eval(ctx, "return 1", []string{"a", "b"}, 3)
+	if want := []interface{}{"EVAL", "return 1", 2, "a", "b", 3}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+	if err := NewScript("return 1").Load(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{"SCRIPT", "LOAD", "return 1"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+	exists, err := c.ScriptExists(ctx, "a", "b").Result()
+	if err != nil || !reflect.DeepEqual(exists, []bool{true, false}) {
+		t.Fatalf("got %v, %v", exists, err)
+	}
+	if want := []interface{}{"SCRIPT", "EXISTS", "a", "b"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+	c.ScriptFlush(ctx, "ASYNC")
+	if want := []interface{}{"SCRIPT", "FLUSH", "async"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+
+	cmd := newBoolSliceCmd(ctx)
+	if err := cmd.ReadReply([]interface{}{"x"}); err == nil {
+		t.Fatal("want error for a non-integer element")
+	}
+}
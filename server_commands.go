@@ -0,0 +1,408 @@
+package go_redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ServerCmdable interface {
+	Ping(ctx context.Context) *StatusCmd
+	Echo(ctx context.Context, message interface{}) *StringCmd
+	Info(ctx context.Context, sections ...string) *StringCmd
+	InfoMap(ctx context.Context, sections ...string) *InfoCmd
+	DBSize(ctx context.Context) *IntCmd
+	FlushDB(ctx context.Context, mode string) *StatusCmd
+	FlushAll(ctx context.Context, mode string) *StatusCmd
+	Time(ctx context.Context) *TimeCmd
+	LastSave(ctx context.Context) *IntCmd
+	BgSave(ctx context.Context) *StatusCmd
+	BgRewriteAOF(ctx context.Context) *StatusCmd
+	ConfigGet(ctx context.Context, parameter string) *MapStringStringCmd
+	ConfigSet(ctx context.Context, parameter, value string) *StatusCmd
+	ConfigRewrite(ctx context.Context) *StatusCmd
+	ConfigResetStat(ctx context.Context) *StatusCmd
+	Shutdown(ctx context.Context, mode string) *StatusCmd
+}
+
+// FLUSHDB / FLUSHALL modes. Empty uses the server's lazyfree-lazy-user-flush.
+const (
+	FlushAsync = "ASYNC"
+	FlushSync  = "SYNC"
+)
+
+// SHUTDOWN modes. Empty saves only if save points are configured.
+const (
+	ShutdownSave   = "SAVE"
+	ShutdownNoSave = "NOSAVE"
+)
+
+func (c cmdable) Ping(ctx context.Context) *StatusCmd {
+	cmd := newStatusCmd(ctx, "PING")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) Echo(ctx context.Context, message interface{}) *StringCmd {
+	cmd := newStringCmd(ctx, "ECHO", message)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) Info(ctx context.Context, sections ...string) *StringCmd {
+	cmd := newStringCmd(ctx, infoArgs(sections)...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// InfoMap runs INFO and parses the reply by section.
+func (c cmdable) InfoMap(ctx context.Context, sections ...string) *InfoCmd {
+	cmd := &InfoCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: infoArgs(sections),
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func infoArgs(sections []string) []interface{} {
+	args := make([]interface{}, 1, 1+len(sections))
+	args[0] = "INFO"
+	for _, section := range sections {
+		args = append(args, section)
+	}
+	return args
+}
+
+func (c cmdable) DBSize(ctx context.Context) *IntCmd {
+	cmd := newIntCmd(ctx, "DBSIZE")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) FlushDB(ctx context.Context, mode string) *StatusCmd {
+	args := []interface{}{"FLUSHDB"}
+	if mode != "" {
+		args = append(args, strings.ToLower(mode))
+	}
+	cmd := newStatusCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) FlushAll(ctx context.Context, mode string) *StatusCmd {
+	args := []interface{}{"FLUSHALL"}
+	if mode != "" {
+		args = append(args, strings.ToLower(mode))
+	}
+	cmd := newStatusCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) Time(ctx context.Context) *TimeCmd {
+	cmd := &TimeCmd{
+		baseCmd: &baseCmd{
+			ctx:  ctx,
+			args: []interface{}{"TIME"},
+		},
+	}
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) LastSave(ctx context.Context) *IntCmd {
+	cmd := newIntCmd(ctx, "LASTSAVE")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) BgSave(ctx context.Context) *StatusCmd {
+	cmd := newStatusCmd(ctx, "BGSAVE")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) BgRewriteAOF(ctx context.Context) *StatusCmd {
+	cmd := newStatusCmd(ctx, "BGREWRITEAOF")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ConfigGet(ctx context.Context, parameter string) *MapStringStringCmd {
+	cmd := newMapStringStringCmd(ctx, "CONFIG", "GET", parameter)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ConfigSet(ctx context.Context, parameter, value string) *StatusCmd {
+	cmd := newStatusCmd(ctx, "CONFIG", "SET", parameter, value)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ConfigRewrite(ctx context.Context) *StatusCmd {
+	cmd := newStatusCmd(ctx, "CONFIG", "REWRITE")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ConfigResetStat(ctx context.Context) *StatusCmd {
+	cmd := newStatusCmd(ctx, "CONFIG", "RESETSTAT")
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// shutdownCmd is never retried, the server may be gone after running it.
+type shutdownCmd struct {
+	*StatusCmd
+}
+
+func (cmd *shutdownCmd) nonRetryable() {}
+
+// Shutdown stops the server. A successful shutdown closes the connection,
+// so io.EOF is reported as success.
+func (c cmdable) Shutdown(ctx context.Context, mode string) *StatusCmd {
+	args := []interface{}{"SHUTDOWN"}
+	if mode != "" {
+		args = append(args, strings.ToLower(mode))
+	}
+	cmd := &shutdownCmd{newStatusCmd(ctx, args...)}
+	if err := c(ctx, cmd); err != nil && !errors.Is(err, io.EOF) {
+		cmd.err = err
+	}
+	return cmd.StatusCmd
+}
+
+type TimeCmd struct {
+	*baseCmd
+	result time.Time
+}
+
+func (cmd *TimeCmd) ReadReply(val interface{}) error {
+	vals, err := toSlice(val)
+	if err != nil {
+		return err
+	}
+	if len(vals) != 2 {
+		return fmt.Errorf("redis: got %d elements in time reply, want 2", len(vals))
+	}
+	sec, err := toInt64(vals[0])
+	if err != nil {
+		return err
+	}
+	usec, err := toInt64(vals[1])
+	if err != nil {
+		return err
+	}
+	cmd.result = time.Unix(sec, usec*int64(time.Microsecond))
+	return nil
+}
+
+func (cmd *TimeCmd) String() string {
+	return cmd.result.String()
+}
+
+func (cmd *TimeCmd) Result() (time.Time, error) {
+	return cmd.result, cmd.err
+}
+
+// InfoCmd holds INFO as map[section]map[key]value. Section names are
+// lower-cased, e.g. "memory", "replication", "keyspace".
+type InfoCmd struct {
+	*baseCmd
+	result map[string]map[string]string
+}
+
+func (cmd *InfoCmd) ReadReply(val interface{}) error {
+	s, err := toString(val)
+	if err != nil {
+		return err
+	}
+	cmd.result = parseInfo(s)
+	return nil
+}
+
+func (cmd *InfoCmd) String() string {
+	return fmt.Sprint(cmd.result)
+}
+
+func (cmd *InfoCmd) Result() (map[string]map[string]string, error) {
+	return cmd.result, cmd.err
+}
+
+// Item returns a single INFO field, or "" if absent.
+func (cmd *InfoCmd) Item(section, key string) string {
+	return cmd.result[strings.ToLower(section)][key]
+}
+
+// Info returns the common INFO fields as typed values.
+func (cmd *InfoCmd) Info() (*ServerInfo, error) {
+	if cmd.err != nil {
+		return nil, cmd.err
+	}
+	return newServerInfo(cmd.result), nil
+}
+
+func parseInfo(s string) map[string]map[string]string {
+	info := make(map[string]map[string]string)
+	section := ""
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if line[0] == '#' {
+			section = strings.ToLower(strings.TrimSpace(line[1:]))
+			if info[section] == nil {
+				info[section] = make(map[string]string)
+			}
+			continue
+		}
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if info[section] == nil {
+			info[section] = make(map[string]string)
+		}
+		info[section][k] = v
+	}
+	return info
+}
+
+type ServerInfo struct {
+	Clients     ClientsInfo
+	Memory      MemoryInfo
+	Replication ReplicationInfo
+	// keyed by database number
+	Keyspace map[int]KeyspaceInfo
+}
+
+type ClientsInfo struct {
+	ConnectedClients int64
+	BlockedClients   int64
+	TrackingClients  int64
+	MaxClients       int64
+}
+
+type MemoryInfo struct {
+	UsedMemory            int64
+	UsedMemoryRSS         int64
+	UsedMemoryPeak        int64
+	UsedMemoryLua         int64
+	MaxMemory             int64
+	MaxMemoryPolicy       string
+	MemFragmentationRatio float64
+}
+
+type ReplicationInfo struct {
+	Role             string
+	ConnectedSlaves  int64
+	MasterHost       string
+	MasterPort       int64
+	MasterLinkStatus string
+	MasterReplOffset int64
+	Replicas         []ReplicaInfo
+}
+
+type ReplicaInfo struct {
+	IP     string
+	Port   int64
+	State  string
+	Offset int64
+	Lag    int64
+}
+
+type KeyspaceInfo struct {
+	Keys    int64
+	Expires int64
+	AvgTTL  time.Duration
+}
+
+func newServerInfo(info map[string]map[string]string) *ServerInfo {
+	clients := info["clients"]
+	memory := info["memory"]
+	repl := info["replication"]
+
+	si := &ServerInfo{
+		Clients: ClientsInfo{
+			ConnectedClients: parseInfoInt(clients["connected_clients"]),
+			BlockedClients:   parseInfoInt(clients["blocked_clients"]),
+			TrackingClients:  parseInfoInt(clients["tracking_clients"]),
+			MaxClients:       parseInfoInt(clients["maxclients"]),
+		},
+		Memory: MemoryInfo{
+			UsedMemory:            parseInfoInt(memory["used_memory"]),
+			UsedMemoryRSS:         parseInfoInt(memory["used_memory_rss"]),
+			UsedMemoryPeak:        parseInfoInt(memory["used_memory_peak"]),
+			UsedMemoryLua:         parseInfoInt(memory["used_memory_lua"]),
+			MaxMemory:             parseInfoInt(memory["maxmemory"]),
+			MaxMemoryPolicy:       memory["maxmemory_policy"],
+			MemFragmentationRatio: parseInfoFloat(memory["mem_fragmentation_ratio"]),
+		},
+		Replication: ReplicationInfo{
+			Role:             repl["role"],
+			ConnectedSlaves:  parseInfoInt(repl["connected_slaves"]),
+			MasterHost:       repl["master_host"],
+			MasterPort:       parseInfoInt(repl["master_port"]),
+			MasterLinkStatus: repl["master_link_status"],
+			MasterReplOffset: parseInfoInt(repl["master_repl_offset"]),
+		},
+		Keyspace: make(map[int]KeyspaceInfo),
+	}
+
+	for i := int64(0); i < si.Replication.ConnectedSlaves; i++ {
+		fields := parseInfoFields(repl["slave"+strconv.FormatInt(i, 10)])
+		si.Replication.Replicas = append(si.Replication.Replicas, ReplicaInfo{
+			IP:     fields["ip"],
+			Port:   parseInfoInt(fields["port"]),
+			State:  fields["state"],
+			Offset: parseInfoInt(fields["offset"]),
+			Lag:    parseInfoInt(fields["lag"]),
+		})
+	}
+
+	for db, v := range info["keyspace"] {
+		n, err := strconv.Atoi(strings.TrimPrefix(db, "db"))
+		if err != nil {
+			continue
+		}
+		fields := parseInfoFields(v)
+		si.Keyspace[n] = KeyspaceInfo{
+			Keys:    parseInfoInt(fields["keys"]),
+			Expires: parseInfoInt(fields["expires"]),
+			AvgTTL:  time.Duration(parseInfoInt(fields["avg_ttl"])) * time.Millisecond,
+		}
+	}
+
+	return si
+}
+
+// parseInfoFields parses "k1=v1,k2=v2" values such as db0 and slave0.
+func parseInfoFields(s string) map[string]string {
+	fields := make(map[string]string)
+	for _, kv := range strings.Split(s, ",") {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// INFO fields are informational, a malformed value reads as zero.
+func parseInfoInt(s string) int64 {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
+
+func parseInfoFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
@@ -0,0 +1,57 @@
+package go_redis
+
+import (
+	"testing"
+	"time"
+)
+
+const infoReply = "# Clients\r\n" +
+	"connected_clients:3\r\n" +
+	"blocked_clients:1\r\n" +
+	"maxclients:10000\r\n" +
+	"\r\n" +
+	"# Memory\r\n" +
+	"used_memory:1048576\r\n" +
+	"maxmemory_policy:allkeys-lru\r\n" +
+	"mem_fragmentation_ratio:1.25\r\n" +
+	"\r\n" +
+	"# Replication\r\n" +
+	"role:master\r\n" +
+	"connected_slaves:1\r\n" +
+	"slave0:ip=10.0.0.2,port=6380,state=online,offset=42,lag=0\r\n" +
+	"master_repl_offset:42\r\n" +
+	"\r\n" +
+	"# Keyspace\r\n" +
+	"db0:keys=12,expires=2,avg_ttl=1500,subexpiry=0\r\n" +
+	"db3:keys=1,expires=0,avg_ttl=0\r\n"
+
+func TestInfoCmd(t *testing.T) {
+	cmd := &InfoCmd{baseCmd: &baseCmd{}}
+	if err := cmd.ReadReply(infoReply); err != nil {
+		t.Fatal(err)
+	}
+	if got := cmd.Item("Memory", "maxmemory_policy"); got != "allkeys-lru" {
+		t.Fatalf("got maxmemory_policy %q", got)
+	}
+
+	info, err := cmd.Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Clients.ConnectedClients != 3 || info.Clients.MaxClients != 10000 {
+		t.Fatalf("got clients %+v", info.Clients)
+	}
+	if info.Memory.UsedMemory != 1048576 || info.Memory.MemFragmentationRatio != 1.25 {
+		t.Fatalf("got memory %+v", info.Memory)
+	}
+	if len(info.Replication.Replicas) != 1 || info.Replication.Replicas[0].Port != 6380 {
+		t.Fatalf("got replication %+v", info.Replication)
+	}
+	want := KeyspaceInfo{Keys: 12, Expires: 2, AvgTTL: 1500 * time.Millisecond}
+	if got := info.Keyspace[0]; got != want {
+		t.Fatalf("got db0 %+v, want %+v", got, want)
+	}
+	if got := info.Keyspace[3].Keys; got != 1 {
+		t.Fatalf("got db3 keys %d, want 1", got)
+	}
+}
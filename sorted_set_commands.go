@@ -0,0 +1,61 @@
+package go_redis
+
+import "context"
+
+type SortedSetCmdable interface {
+	ZAdd(ctx context.Context, key string, members ...Z) *IntCmd
+	ZRem(ctx context.Context, key string, members ...interface{}) *IntCmd
+	ZCard(ctx context.Context, key string) *IntCmd
+	ZScore(ctx context.Context, key, member string) *FloatCmd
+	ZRange(ctx context.Context, key string, start, stop int64) *StringSliceCmd
+}
+
+// Z is a member of a sorted set.
+type Z struct {
+	Score  float64
+	Member interface{}
+}
+
+// ZAdd returns the number of members added.
+func (c cmdable) ZAdd(ctx context.Context, key string, members ...Z) *IntCmd {
+	args := make([]interface{}, 2, 2+2*len(members))
+	args[0] = "ZADD"
+	args[1] = key
+	for _, m := range members {
+		args = append(args, m.Score, m.Member)
+	}
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// ZRem returns the number of members removed.
+func (c cmdable) ZRem(ctx context.Context, key string, members ...interface{}) *IntCmd {
+	args := make([]interface{}, 2, 2+len(members))
+	args[0] = "ZREM"
+	args[1] = key
+	args = append(args, members...)
+	cmd := newIntCmd(ctx, args...)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+func (c cmdable) ZCard(ctx context.Context, key string) *IntCmd {
+	cmd := newIntCmd(ctx, "ZCARD", key)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// ZScore returns proto.Nil if member is not in the set.
+func (c cmdable) ZScore(ctx context.Context, key, member string) *FloatCmd {
+	cmd := newFloatCmd(ctx, "ZSCORE", key, member)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
+
+// ZRange returns the members ranked start to stop by ascending score.
+func (c cmdable) ZRange(ctx context.Context, key string, start, stop int64) *StringSliceCmd {
+	cmd := newStringSliceCmd(ctx, "ZRANGE", key, start, stop)
+	cmd.err = c(ctx, cmd)
+	return cmd
+}
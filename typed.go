@@ -0,0 +1,83 @@
+package go_redis
+
+import (
+	"context"
+	"errors"
+	"github.com/mingolm/go-redis/proto"
+	"time"
+)
+
+// TypedKey stores values of type T encoded with a Codec:
+//
+//	users := redis.Typed[User](client, redis.JSONCodec{})
+//	err := users.Set(ctx, "user:1", u, time.Hour)
+//	u, err := users.Get(ctx, "user:1")
+type TypedKey[T any] struct {
+	c     Cmdable
+	codec Codec
+}
+
+func Typed[T any](c Cmdable, codec Codec) *TypedKey[T] {
+	return &TypedKey[T]{
+		c:     c,
+		codec: codec,
+	}
+}
+
+func (k *TypedKey[T]) Set(ctx context.Context, key string, val T, expiration time.Duration) error {
+	data, err := k.codec.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return k.c.Set(ctx, key, data, expiration).Err()
+}
+
+// Get returns proto.Nil if key does not exist.
+func (k *TypedKey[T]) Get(ctx context.Context, key string) (val T, err error) {
+	data, err := k.c.GetBytes(ctx, key).Result()
+	if err != nil {
+		return val, err
+	}
+	err = k.codec.Unmarshal(data, &val)
+	return val, err
+}
+
+// MGet returns the values of the existing keys.
+func (k *TypedKey[T]) MGet(ctx context.Context, keys ...string) (map[string]T, error) {
+	vals, err := k.c.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]T, len(vals))
+	for i, v := range vals {
+		if v == nil {
+			continue
+		}
+		s, err := toString(v)
+		if err != nil {
+			return nil, err
+		}
+		var val T
+		if err := k.codec.Unmarshal([]byte(s), &val); err != nil {
+			return nil, err
+		}
+		m[keys[i]] = val
+	}
+	return m, nil
+}
+
+// GetOrSet returns the value of key, or stores and returns the value from
+// loader if key does not exist. A failed Set is returned along with the
+// loaded value.
+func (k *TypedKey[T]) GetOrSet(ctx context.Context, key string, expiration time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	val, err := k.Get(ctx, key)
+	if !errors.Is(err, proto.Nil) {
+		return val, err
+	}
+
+	if val, err = loader(ctx); err != nil {
+		return val, err
+	}
+	return val, k.Set(ctx, key, val, expiration)
+}
@@ -0,0 +1,84 @@
+package go_redis
+
+import (
+	"context"
+	"errors"
+	"github.com/mingolm/go-redis/proto"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// memCmdable serves SET, GET and MGET from a map.
+func memCmdable(store map[string]string) cmdable {
+	return func(ctx context.Context, cmd Cmder) error {
+		args := cmd.Args()
+		switch args[0] {
+		case "SET":
+			store[args[1].(string)] = string(args[2].([]byte))
+			return cmd.ReadReply("OK")
+		case "GET":
+			v, ok := store[args[1].(string)]
+			if !ok {
+				return proto.Nil
+			}
+			return cmd.ReadReply(v)
+		case "MGET":
+			vals := make([]interface{}, 0, len(args)-1)
+			for _, key := range args[1:] {
+				if v, ok := store[key.(string)]; ok {
+					vals = append(vals, v)
+				} else {
+					vals = append(vals, nil)
+				}
+			}
+			return cmd.ReadReply(vals)
+		}
+		return errors.New("unexpected command")
+	}
+}
+
+type typedUser struct {
+	Name string
+	Tags []string
+}
+
+func TestTypedKey(t *testing.T) {
+	ctx := context.Background()
+	for _, codec := range []Codec{JSONCodec{}, GobCodec{}} {
+		store := make(map[string]string)
+		users := Typed[typedUser](memCmdable(store), codec)
+
+		u := typedUser{Name: "mingo", Tags: []string{"a", "b"}}
+		if err := users.Set(ctx, "user:1", u, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+		got, err := users.Get(ctx, "user:1")
+		if err != nil || !reflect.DeepEqual(got, u) {
+			t.Fatalf("%T: got %+v, %v", codec, got, err)
+		}
+		if _, err := users.Get(ctx, "user:2"); !errors.Is(err, proto.Nil) {
+			t.Fatalf("%T: got %v, want Nil", codec, err)
+		}
+
+		m, err := users.MGet(ctx, "user:1", "user:2")
+		if err != nil || len(m) != 1 || m["user:1"].Name != "mingo" {
+			t.Fatalf("%T: got %+v, %v", codec, m, err)
+		}
+
+		var loads int
+		loader := func(ctx context.Context) (typedUser, error) {
+			loads++
+			return typedUser{Name: "loaded"}, nil
+		}
+		for i := 0; i < 2; i++ {
+			got, err = users.GetOrSet(ctx, "user:3", time.Minute, loader)
+			if err != nil || got.Name != "loaded" {
+				t.Fatalf("%T: got %+v, %v", codec, got, err)
+			}
+		}
+		if loads != 1 {
+			t.Fatalf("%T: got %d loads, want 1", codec, loads)
+		}
+	}
+}
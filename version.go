@@ -0,0 +1,8 @@
+package go_redis
+
+const (
+	// LibName is reported to the server with CLIENT SETINFO lib-name.
+	LibName = "mingolm-go-redis"
+	// Version is reported to the server with CLIENT SETINFO lib-ver.
+	Version = "0.1.0"
+)